@@ -0,0 +1,93 @@
+package sacn
+
+import (
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+// GetSlots returns the current 512 slot values of a universe.
+// Slot 1 (the first DMX address) is at index 0.
+func (s *Sender) GetSlots(universe uint16) ([]byte, error) {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		defer uni.mu.Unlock()
+		levels := make([]byte, 512)
+		copy(levels, uni.levels[:])
+		return levels, nil
+	}
+	return nil, universeNotFound(universe)
+}
+
+// SetSlot sets the value of a single slot (1 to 512) of a universe.
+//
+// Each started universe keeps the state of its 512 slots. Changes are sent automatically, respecting the maximum frame rate of the universe.
+// The state is also updated by the level data (Start Code 0) of packets sent with [Sender.Send] or the universe's channel.
+func (s *Sender) SetSlot(universe uint16, slot int, value byte) error {
+	return s.SetSlots(universe, slot, []byte{value})
+}
+
+// SetSlot16 sets a 16-bit value on 2 consecutive slots of a universe: the coarse (most significant) byte on slot and the fine byte on slot+1.
+func (s *Sender) SetSlot16(universe uint16, slot int, value uint16) error {
+	return s.SetSlots(universe, slot, []byte{byte(value >> 8), byte(value & 0xFF)})
+}
+
+// SetSlots sets the values of consecutive slots of a universe, starting at slot start (1 to 512).
+func (s *Sender) SetSlots(universe uint16, start int, values []byte) error {
+	if start < 1 || start+len(values)-1 > 512 {
+		return ErrSlotOutOfRange
+	}
+	return s.updateLevels(universe, start, len(values), func(levels []byte) {
+		copy(levels[start-1:], values)
+	})
+}
+
+// ClearSlots sets all the slots of a universe to 0.
+func (s *Sender) ClearSlots(universe uint16) error {
+	return s.updateLevels(universe, 1, 512, func(levels []byte) {
+		clear(levels)
+	})
+}
+
+func (s *Sender) updateLevels(universe uint16, start int, length int, update func(levels []byte)) error {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		uni.cancelFades(start, length)
+		update(uni.levels[:])
+		uni.levelsChanged = true
+		uni.mu.Unlock()
+		uni.wake()
+		return nil
+	}
+	return universeNotFound(universe)
+}
+
+// storeLevels updates the state of the universe from level data being sent, without sending it again.
+func (uni *senderUniverse) storeLevels(d *packet.DataPacket) {
+	uni.mu.Lock()
+	defer uni.mu.Unlock()
+	length := int(d.Length & 0x0FFF) // includes the Start Code
+	if length > len(d.Data) {
+		length = len(d.Data)
+	}
+	uni.cancelFades(1, 512)
+	clear(uni.levels[:])
+	if length > 1 {
+		copy(uni.levels[:], d.Data[1:length])
+	}
+}
+
+// levelsPacket returns a DataPacket of the universe's state if it changed since the last call, nil otherwise.
+func (s *Sender) levelsPacket(uni *senderUniverse) *packet.DataPacket {
+	uni.mu.Lock()
+	defer uni.mu.Unlock()
+	if !uni.levelsChanged {
+		return nil
+	}
+	uni.levelsChanged = false
+
+	p := packet.NewDataPacket()
+	p.Universe = uni.number
+	p.SetData(uni.levels[:])
+	return p
+}
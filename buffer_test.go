@@ -0,0 +1,73 @@
+package sacn
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSenderSlots(t *testing.T) {
+	s, err := NewSender("127.0.0.1", &SenderOptions{})
+	if err != nil {
+		t.Fatalf("Could not create sender: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.SetSlot(1, 1, 255); !errors.Is(err, ErrUniverseNotFound) {
+		t.Fatalf("Unexpected error on universe not started: %v", err)
+	}
+	s.StartUniverse(1)
+
+	tests := []struct {
+		name     string
+		update   func() error
+		expected []byte // first slots of the universe
+		err      error
+	}{
+		{
+			name:     "Set slot",
+			update:   func() error { return s.SetSlot(1, 1, 255) },
+			expected: []byte{255, 0, 0, 0, 0},
+		},
+		{
+			name:     "Set 16-bit slot",
+			update:   func() error { return s.SetSlot16(1, 2, 0x1234) },
+			expected: []byte{255, 0x12, 0x34, 0, 0},
+		},
+		{
+			name:     "Set slots",
+			update:   func() error { return s.SetSlots(1, 4, []byte{1, 2}) },
+			expected: []byte{255, 0x12, 0x34, 1, 2},
+		},
+		{
+			name:     "Slot 0",
+			update:   func() error { return s.SetSlot(1, 0, 1) },
+			expected: []byte{255, 0x12, 0x34, 1, 2},
+			err:      ErrSlotOutOfRange,
+		},
+		{
+			name:     "Slots past 512",
+			update:   func() error { return s.SetSlot16(1, 512, 1) },
+			expected: []byte{255, 0x12, 0x34, 1, 2},
+			err:      ErrSlotOutOfRange,
+		},
+		{
+			name:     "Clear",
+			update:   func() error { return s.ClearSlots(1) },
+			expected: []byte{0, 0, 0, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		if err := tt.update(); !errors.Is(err, tt.err) {
+			t.Fatalf("unexpected error on \"%s\":\n- want: %v\n-  got: %v", tt.name, tt.err, err)
+		}
+		slots, _ := s.GetSlots(1)
+		if len(slots) != 512 {
+			t.Fatalf("unexpected number of slots on \"%s\": %d", tt.name, len(slots))
+		}
+		if !bytes.Equal(tt.expected, slots[:len(tt.expected)]) {
+			t.Fatalf("unexpected slots on \"%s\":\n- want: %v\n-  got: %v", tt.name, tt.expected, slots[:len(tt.expected)])
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package sacn
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+// Namespace of the name-based CIDs created by [NewNameCID]
+var cidNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("gitlab.com/patopest/go-sacn"))
+
+// NewCID generates a new random CID (time-ordered UUIDv7).
+// A device should keep its CID across restarts, see [LoadOrCreateCID] and [NewNameCID].
+func NewCID() (packet.CID, error) {
+	cid, err := uuid.NewV7()
+	if err != nil {
+		return packet.CID{}, err
+	}
+	return packet.CID(cid), nil
+}
+
+// NewNameCID derives a stable CID (name-based UUIDv5) from the identity of a device, such as its serial number or MAC address.
+// The same name always gives the same CID.
+func NewNameCID(name string) packet.CID {
+	return packet.CID(uuid.NewSHA1(cidNamespace, []byte(name)))
+}
+
+// LoadOrCreateCID reads the CID stored in the file at path.
+// If the file does not exist, a new CID is generated with [NewCID] and written to it, so it is used on the next start.
+// An error is returned if the file exists but does not contain a valid CID.
+func LoadOrCreateCID(path string) (packet.CID, error) {
+	b, err := os.ReadFile(path)
+	if err == nil {
+		cid, err := packet.ParseCID(strings.TrimSpace(string(b)))
+		if err != nil {
+			return packet.CID{}, fmt.Errorf("Invalid CID in %s: %w", path, err)
+		}
+		return cid, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return packet.CID{}, err
+	}
+
+	cid, err := NewCID()
+	if err != nil {
+		return packet.CID{}, err
+	}
+	// Write to a temporary file first so a partially written CID is never read
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return packet.CID{}, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(cid.String() + "\n"); err != nil {
+		tmp.Close()
+		return packet.CID{}, err
+	}
+	if err := tmp.Close(); err != nil {
+		return packet.CID{}, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return packet.CID{}, err
+	}
+	return cid, nil
+}
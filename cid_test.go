@@ -0,0 +1,47 @@
+package sacn
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewNameCID(t *testing.T) {
+	a := NewNameCID("00:11:22:33:44:55")
+	b := NewNameCID("00:11:22:33:44:55")
+	c := NewNameCID("00:11:22:33:44:56")
+
+	if a != b {
+		t.Fatalf("unexpected CID for the same name:\n- want: %v\n-  got: %v", a, b)
+	}
+	if a == c {
+		t.Fatalf("same CID for different names: %v", a)
+	}
+	if version := a[6] >> 4; version != 5 {
+		t.Fatalf("unexpected UUID version:\n- want: %d\n-  got: %d", 5, version)
+	}
+}
+
+func TestLoadOrCreateCID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cid")
+
+	created, err := LoadOrCreateCID(path)
+	if err != nil {
+		t.Fatalf("Could not create CID: %v", err)
+	}
+	if created.IsZero() {
+		t.Fatalf("Created CID is empty")
+	}
+	loaded, err := LoadOrCreateCID(path)
+	if err != nil {
+		t.Fatalf("Could not load CID: %v", err)
+	}
+	if loaded != created {
+		t.Fatalf("unexpected loaded CID:\n- want: %v\n-  got: %v", created, loaded)
+	}
+
+	os.WriteFile(path, []byte("not a cid\n"), 0644)
+	if _, err := LoadOrCreateCID(path); err == nil {
+		t.Fatalf("No error returned on invalid CID file")
+	}
+}
@@ -3,6 +3,9 @@ package sacn
 import (
 	"fmt"
 	"net"
+	"time"
+
+	"gitlab.com/patopest/go-sacn/packet"
 )
 
 const (
@@ -10,6 +13,10 @@ const (
 	DISCOVERY_UNIVERSE          = 64214 // the universe used for universe discovery
 	UNIVERSE_DISCOVERY_INTERVAL = 10    // in seconds
 	NETWORK_DATA_LOSS_TIMEOUT   = 2500  // in milliseconds
+	DEFAULT_KEEP_ALIVE_INTERVAL = 1000  // in milliseconds
+	DMX_FRAME_RATE              = 44    // in frames per second, the maximum refresh rate of a full DMX512-A universe
+
+	PER_ADDRESS_PRIORITY_INTERVAL = 1000 // in milliseconds, the de-facto interval of per-address priority (0xDD) packets
 )
 
 // Section 9.3 of spec
@@ -29,3 +36,41 @@ func checkSequence(A uint8, B uint8) bool {
 	}
 	return true
 }
+
+// Name of a packet type in log attributes
+func packetTypeName(packetType packet.SACNPacketType) string {
+	switch packetType {
+	case packet.PacketTypeData:
+		return "data"
+	case packet.PacketTypeSync:
+		return "sync"
+	case packet.PacketTypeDiscovery:
+		return "discovery"
+	default:
+		return "unknown"
+	}
+}
+
+// CID of any packet type
+func packetCID(p packet.SACNPacket) packet.CID {
+	switch d := p.(type) {
+	case *packet.DataPacket:
+		return d.CID
+	case *packet.SyncPacket:
+		return d.CID
+	case *packet.DiscoveryPacket:
+		return d.CID
+	}
+	return packet.CID{}
+}
+
+// Stops a timer and drains its channel if needed before resetting it, so no stale tick is received.
+func resetTimer(t *time.Timer, d time.Duration) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+	t.Reset(d)
+}
@@ -0,0 +1,53 @@
+//go:build linux
+
+package sacn
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// enableControlMessages asks the kernel to attach the receive timestamp (SO_TIMESTAMPNS)
+// and the number of packets dropped by the receive queue of the socket (SO_RXQ_OVFL) to received packets.
+func enableControlMessages(conn *net.UDPConn) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return
+	}
+	raw.Control(func(fd uintptr) {
+		syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_TIMESTAMPNS, 1)
+		syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_RXQ_OVFL, 1)
+	})
+}
+
+// parseControlMessages returns the information found in the control messages of a received packet.
+func parseControlMessages(oob []byte) controlInfo {
+	var info controlInfo
+	msgs, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return info
+	}
+	for _, msg := range msgs {
+		if msg.Header.Level != syscall.SOL_SOCKET {
+			continue
+		}
+		switch {
+		case msg.Header.Type == syscall.SCM_TIMESTAMPNS && len(msg.Data) >= int(unsafe.Sizeof(syscall.Timespec{})):
+			ts := (*syscall.Timespec)(unsafe.Pointer(&msg.Data[0]))
+			info.timestamp = time.Unix(ts.Unix())
+			info.hasTimestamp = true
+		case msg.Header.Type == syscall.SO_RXQ_OVFL && len(msg.Data) >= 4:
+			info.overflows = binary.NativeEndian.Uint32(msg.Data)
+			info.hasOverflows = true
+		}
+	}
+	return info
+}
+
+// isTruncated returns true if the flags of a received message tell it was larger than the read buffer.
+func isTruncated(flags int) bool {
+	return flags&syscall.MSG_TRUNC != 0
+}
@@ -0,0 +1,94 @@
+//go:build linux
+
+package sacn
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+// Returns a control message of the socket level with data
+func controlMessage(msgType int32, data []byte) []byte {
+	b := make([]byte, syscall.CmsgSpace(len(data)))
+	h := (*syscall.Cmsghdr)(unsafe.Pointer(&b[0]))
+	h.Level = syscall.SOL_SOCKET
+	h.Type = msgType
+	h.SetLen(syscall.CmsgLen(len(data)))
+	copy(b[syscall.CmsgLen(0):], data)
+	return b
+}
+
+func TestParseControlMessages(t *testing.T) {
+	timestamp := time.Unix(1700000000, 123456789)
+	ts := syscall.NsecToTimespec(timestamp.UnixNano())
+	tsBytes := unsafe.Slice((*byte)(unsafe.Pointer(&ts)), unsafe.Sizeof(ts))
+	overflows := binary.NativeEndian.AppendUint32(nil, 42)
+
+	tests := []struct {
+		name     string
+		oob      []byte
+		expected controlInfo
+	}{
+		{name: "No control messages", oob: nil, expected: controlInfo{}},
+		{name: "Timestamp", oob: controlMessage(syscall.SCM_TIMESTAMPNS, tsBytes), expected: controlInfo{timestamp: timestamp, hasTimestamp: true}},
+		{name: "Overflows", oob: controlMessage(syscall.SO_RXQ_OVFL, overflows), expected: controlInfo{overflows: 42, hasOverflows: true}},
+		{
+			name:     "Timestamp and overflows",
+			oob:      append(controlMessage(syscall.SCM_TIMESTAMPNS, tsBytes), controlMessage(syscall.SO_RXQ_OVFL, overflows)...),
+			expected: controlInfo{timestamp: timestamp, hasTimestamp: true, overflows: 42, hasOverflows: true},
+		},
+		{name: "Short overflows", oob: controlMessage(syscall.SO_RXQ_OVFL, overflows[:2]), expected: controlInfo{}},
+		{name: "Malformed", oob: []byte{0xff, 0xff}, expected: controlInfo{}},
+	}
+
+	for _, tt := range tests {
+		info := parseControlMessages(tt.oob)
+		if !info.timestamp.Equal(tt.expected.timestamp) || info.hasTimestamp != tt.expected.hasTimestamp ||
+			info.overflows != tt.expected.overflows || info.hasOverflows != tt.expected.hasOverflows {
+			t.Fatalf("unexpected control info on \"%s\":\n- want: %+v\n-  got: %+v", tt.name, tt.expected, info)
+		}
+	}
+}
+
+func TestControlMessagesSocket(t *testing.T) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("Could not create socket: %v", err)
+	}
+	defer conn.Close()
+	enableControlMessages(conn)
+
+	sender, err := net.DialUDP("udp4", nil, conn.LocalAddr().(*net.UDPAddr))
+	if err != nil {
+		t.Fatalf("Could not create socket: %v", err)
+	}
+	defer sender.Close()
+
+	buf := make([]byte, packet.MAX_PACKET_SIZE)
+	oob := make([]byte, 256)
+	for _, size := range []int{100, packet.MAX_PACKET_SIZE + 1} {
+		sent := time.Now()
+		if _, err := sender.Write(make([]byte, size)); err != nil {
+			t.Fatalf("Could not send datagram: %v", err)
+		}
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, oobn, flags, _, err := conn.ReadMsgUDP(buf, oob)
+		if err != nil {
+			t.Fatalf("Could not receive datagram: %v", err)
+		}
+
+		if truncated := isTruncated(flags); truncated != (size > len(buf)) {
+			t.Fatalf("unexpected truncation of %d bytes datagram read as %d bytes:\n- want: %v\n-  got: %v", size, n, size > len(buf), truncated)
+		}
+		info := parseControlMessages(oob[:oobn])
+		if !info.hasTimestamp || info.timestamp.Before(sent.Add(-time.Second)) || info.timestamp.After(time.Now()) {
+			t.Fatalf("unexpected kernel timestamp of %d bytes datagram: %+v", size, info)
+		}
+	}
+}
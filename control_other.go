@@ -0,0 +1,20 @@
+//go:build !linux
+
+package sacn
+
+import (
+	"net"
+)
+
+// enableControlMessages does nothing, kernel receive timestamps and overflow counts are only supported on Linux.
+func enableControlMessages(conn *net.UDPConn) {}
+
+// parseControlMessages returns no information, kernel receive timestamps and overflow counts are only supported on Linux.
+func parseControlMessages(oob []byte) controlInfo {
+	return controlInfo{}
+}
+
+// isTruncated returns false, truncated packets are only reported on Linux.
+func isTruncated(flags int) bool {
+	return false
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package sacn
+
+import (
+	"testing"
+)
+
+func TestParseControlMessages(t *testing.T) {
+	if info := parseControlMessages([]byte{0x01, 0x02, 0x03, 0x04}); info != (controlInfo{}) {
+		t.Fatalf("unexpected control info:\n- want: %+v\n-  got: %+v", controlInfo{}, info)
+	}
+	if isTruncated(-1) {
+		t.Fatalf("unexpected truncated packet")
+	}
+}
@@ -0,0 +1,138 @@
+package sacn
+
+import (
+	"sort"
+	"time"
+
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+// Delay between a change of the advertised universes and the discovery update, so several changes are sent at once
+const DISCOVERY_UPDATE_DELAY = 100 // in milliseconds
+
+// Maximum number of universes in a page of a DiscoveryPacket. From ANSI E1.31—2018 Section 8.5
+const discoveryPageSize = 512
+
+// SetDiscoveryInterval sets the interval at which the universes of the sender are advertised with DiscoveryPackets.
+// Use 0 to disable universe discovery. See Section 4.3 of ANSI E1.31—2018.
+func (s *Sender) SetDiscoveryInterval(interval time.Duration) error {
+	if interval < 0 {
+		return ErrInvalidDiscoveryInterval
+	}
+	s.mu.Lock()
+	s.discoveryInterval = interval
+	s.mu.Unlock()
+	s.discoveryUni.wake()
+	return nil
+}
+
+// GetDiscoveryInterval returns the interval at which the universes of the sender are advertised, 0 if universe discovery is disabled.
+func (s *Sender) GetDiscoveryInterval() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.discoveryInterval
+}
+
+// SetDiscoveryExcluded sets whether a universe is left out of the universes advertised by universe discovery.
+func (s *Sender) SetDiscoveryExcluded(universe uint16, excluded bool) error {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		uni.discoveryExcluded = excluded
+		uni.mu.Unlock()
+		s.discoveryUni.wake()
+		return nil
+	}
+	return universeNotFound(universe)
+}
+
+// IsDiscoveryExcluded returns true if a universe is left out of the universes advertised by universe discovery.
+func (s *Sender) IsDiscoveryExcluded(universe uint16) (bool, error) {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		defer uni.mu.Unlock()
+		return uni.discoveryExcluded, nil
+	}
+	return false, universeNotFound(universe)
+}
+
+func (s *Sender) sendDiscoveryLoop() {
+	defer s.wg.Done()
+
+	// Fires when the next discovery advertisement is due
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+	var timerCh <-chan time.Time
+
+	schedule := func(d time.Duration) {
+		if s.GetDiscoveryInterval() == 0 {
+			timer.Stop()
+			timerCh = nil
+			return
+		}
+		resetTimer(timer, d)
+		timerCh = timer.C
+	}
+	schedule(DISCOVERY_UPDATE_DELAY * time.Millisecond)
+
+	for {
+		select {
+		case <-s.discoveryUni.dataCh: // channel was closed
+			return
+		case <-s.discoveryUni.notify: // advertised universes or interval changed
+			schedule(DISCOVERY_UPDATE_DELAY * time.Millisecond)
+		case <-timerCh:
+			s.sendDiscovery()
+			schedule(s.GetDiscoveryInterval())
+		}
+	}
+}
+
+// sendDiscovery sends the sorted list of advertised universes, split in pages of 512 universes.
+func (s *Sender) sendDiscovery() {
+	universes := s.discoveryUniverses()
+	pages := discoveryPages(len(universes))
+	for page := 0; page < pages; page += 1 {
+		p := packet.NewDiscoveryPacket()
+		p.Page = uint8(page)
+		p.Last = uint8(pages - 1)
+		p.CID = s.cid
+		p.SetSourceName(s.GetSourceName())
+
+		start := page * discoveryPageSize
+		end := min(start+discoveryPageSize, len(universes))
+		p.SetUniverses(universes[start:end])
+
+		s.sendPacket(s.discoveryUni, p)
+	}
+}
+
+// Sorted list of the universes advertised by universe discovery
+func (s *Sender) discoveryUniverses() []uint16 {
+	s.mu.RLock()
+	unis := make([]*senderUniverse, 0, len(s.universes))
+	for _, uni := range s.universes {
+		unis = append(unis, uni)
+	}
+	s.mu.RUnlock()
+
+	universes := make([]uint16, 0, len(unis))
+	for _, uni := range unis {
+		uni.mu.Lock()
+		advertised := uni.enabled && !uni.discoveryExcluded // terminating universes are no longer advertised
+		uni.mu.Unlock()
+		if advertised && !uni.stopped.Load() {
+			universes = append(universes, uni.number)
+		}
+	}
+	sort.Slice(universes, func(i, j int) bool { return universes[i] < universes[j] })
+	return universes
+}
+
+// Number of pages needed to advertise a number of universes. An empty page is sent when there are no universes.
+func discoveryPages(num int) int {
+	pages := (num + discoveryPageSize - 1) / discoveryPageSize
+	return max(pages, 1)
+}
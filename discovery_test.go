@@ -0,0 +1,57 @@
+package sacn
+
+import (
+	"testing"
+)
+
+func TestDiscoveryPages(t *testing.T) {
+	tests := []struct {
+		universes int
+		expected  int
+	}{
+		{universes: 0, expected: 1},
+		{universes: 1, expected: 1},
+		{universes: 512, expected: 1},
+		{universes: 513, expected: 2},
+		{universes: 1024, expected: 2},
+		{universes: 1025, expected: 3},
+	}
+
+	for _, tt := range tests {
+		if pages := discoveryPages(tt.universes); pages != tt.expected {
+			t.Fatalf("unexpected number of pages for %d universes:\n- want: %d\n-  got: %d", tt.universes, tt.expected, pages)
+		}
+	}
+}
+
+func TestDiscoveryUniverses(t *testing.T) {
+	s, err := NewSender("127.0.0.1", &SenderOptions{DiscoveryInterval: -1})
+	if err != nil {
+		t.Fatalf("Could not create sender: %v", err)
+	}
+	defer s.Close()
+
+	if interval := s.GetDiscoveryInterval(); interval != 0 {
+		t.Fatalf("unexpected discovery interval when disabled: %v", interval)
+	}
+	for _, universe := range []uint16{300, 5, 42, 7, 8, 9} {
+		s.StartUniverse(universe)
+	}
+	s.SetDiscoveryExcluded(42, true)
+	s.StopUniverse(8)
+	terminating, _ := s.getUniverse(9)
+	terminating.mu.Lock()
+	terminating.enabled = false
+	terminating.mu.Unlock()
+
+	expected := []uint16{5, 7, 300}
+	universes := s.discoveryUniverses()
+	if len(universes) != len(expected) {
+		t.Fatalf("unexpected universes:\n- want: %v\n-  got: %v", expected, universes)
+	}
+	for i := range expected {
+		if universes[i] != expected[i] {
+			t.Fatalf("unexpected universes:\n- want: %v\n-  got: %v", expected, universes)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package sacn
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DispatchPolicy defines what a [Receiver] does when the callbacks of a universe fall behind and its queue is full.
+// It only applies to packet callbacks: termination callbacks and source events are never dropped.
+// SyncPacket callbacks wait for the callbacks queued on the universes they synchronise, so a slow member universe also delays them.
+type DispatchPolicy int
+
+// Possible dispatch policies for [ReceiverOptions].
+const (
+	DispatchDropOldest DispatchPolicy = iota // Discard the oldest queued callback to make room for the newest one (default).
+	DispatchDropNewest                       // Discard the newest callback, keeping the ones already queued.
+	DispatchBlock                            // Wait for room in the queue. This stalls reception of all universes.
+)
+
+// Default number of callbacks which can be queued per universe.
+const DEFAULT_DISPATCH_QUEUE_SIZE = 16
+
+// Duration after which the worker of a universe without callbacks to run is stopped
+const dispatchIdleTimeout = NETWORK_DATA_LOSS_TIMEOUT * time.Millisecond
+
+// dispatcher runs callbacks in arrival order per universe.
+// Each universe gets its own worker goroutine and bounded queue so universes are still processed in parallel.
+// Workers are stopped when their universe is idle or terminated, and started again on the next callback.
+type dispatcher struct {
+	mu      sync.Mutex
+	workers map[uint16]*dispatchWorker
+	size    int
+	policy  DispatchPolicy
+	dropped atomic.Uint64
+	closed  bool
+	idle    time.Duration // see dispatchIdleTimeout
+}
+
+// A queued callback
+type dispatchJob struct {
+	run     func()
+	control bool // control callbacks are never dropped and do not count towards the queue size
+}
+
+type dispatchWorker struct {
+	mu       sync.Mutex
+	room     *sync.Cond // signalled when jobs are removed from the queue or the worker stops
+	jobs     []dispatchJob
+	data     int  // number of queued jobs which are not control callbacks
+	released bool // the universe was terminated, stop once the queue is empty
+	stopped  bool // no jobs can be queued anymore
+	ready    chan struct{}
+}
+
+func newDispatcher(size int, policy DispatchPolicy) *dispatcher {
+	if size <= 0 {
+		size = DEFAULT_DISPATCH_QUEUE_SIZE
+	}
+	return &dispatcher{
+		workers: make(map[uint16]*dispatchWorker),
+		size:    size,
+		policy:  policy,
+		idle:    dispatchIdleTimeout,
+	}
+}
+
+// dispatch queues a packet callback on the worker of the universe, applying the dispatch policy if the queue is full.
+// It shall only be called from a single goroutine (the receive loop).
+func (d *dispatcher) dispatch(universe uint16, job func()) {
+	d.queue(universe, dispatchJob{run: job})
+}
+
+// dispatchControl queues a callback on the worker of the universe which is never dropped.
+func (d *dispatcher) dispatchControl(universe uint16, job func()) {
+	d.queue(universe, dispatchJob{run: job, control: true})
+}
+
+// dispatchAfter queues a packet callback on the worker of the universe which only runs once the callbacks
+// already queued on the other universes (eg: the members of a sync universe) have run.
+func (d *dispatcher) dispatchAfter(universe uint16, after []uint16, job func()) {
+	var wg sync.WaitGroup
+	for _, other := range after {
+		if other == universe {
+			continue
+		}
+		wg.Add(1)
+		d.dispatchControl(other, wg.Done)
+	}
+	d.dispatch(universe, func() {
+		wg.Wait()
+		job()
+	})
+}
+
+// release stops the worker of a terminated universe once it has run all its queued callbacks.
+func (d *dispatcher) release(universe uint16) {
+	d.mu.Lock()
+	w, exists := d.workers[universe]
+	d.mu.Unlock()
+	if !exists {
+		return
+	}
+	w.mu.Lock()
+	w.released = true
+	w.mu.Unlock()
+	w.signal()
+}
+
+func (d *dispatcher) queue(universe uint16, job dispatchJob) {
+	for {
+		w := d.worker(universe)
+		if w == nil {
+			return
+		}
+		if w.push(job, d) {
+			return
+		}
+		// the worker stopped in the meantime, a new one is started
+	}
+}
+
+func (d *dispatcher) worker(universe uint16) *dispatchWorker {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil
+	}
+	w, exists := d.workers[universe]
+	if !exists {
+		w = &dispatchWorker{
+			jobs:  make([]dispatchJob, 0, d.size),
+			ready: make(chan struct{}, 1),
+		}
+		w.room = sync.NewCond(&w.mu)
+		d.workers[universe] = w
+		go d.run(universe, w)
+	}
+	return w
+}
+
+// push queues a job, applying the dispatch policy to packet callbacks. It returns false if the worker is stopped.
+func (w *dispatchWorker) push(job dispatchJob, d *dispatcher) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for !job.control && !w.stopped && w.data >= d.size {
+		switch d.policy {
+		case DispatchBlock:
+			w.room.Wait()
+		case DispatchDropNewest:
+			d.dropped.Add(1)
+			return true
+		default: // DispatchDropOldest
+			for i, queued := range w.jobs {
+				if !queued.control {
+					w.jobs = append(w.jobs[:i], w.jobs[i+1:]...)
+					w.data -= 1
+					d.dropped.Add(1)
+					break
+				}
+			}
+		}
+	}
+	if w.stopped {
+		return false
+	}
+	w.jobs = append(w.jobs, job)
+	if !job.control {
+		w.data += 1
+	}
+	w.released = false
+	w.signal()
+	return true
+}
+
+// pop returns the oldest queued job, or false if the queue is empty.
+func (w *dispatchWorker) pop() (dispatchJob, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.jobs) == 0 {
+		return dispatchJob{}, false
+	}
+	job := w.jobs[0]
+	w.jobs[0] = dispatchJob{}
+	w.jobs = w.jobs[1:]
+	if !job.control {
+		w.data -= 1
+	}
+	w.room.Broadcast()
+	return job, true
+}
+
+func (w *dispatchWorker) signal() {
+	select {
+	case w.ready <- struct{}{}:
+	default: // already signalled
+	}
+}
+
+// Runs the jobs of a worker until it is stopped
+func (d *dispatcher) run(universe uint16, w *dispatchWorker) {
+	idle := time.NewTimer(d.idle)
+	defer idle.Stop()
+
+	for {
+		for {
+			job, ok := w.pop()
+			if !ok {
+				break
+			}
+			job.run()
+		}
+		resetTimer(idle, d.idle)
+
+		w.mu.Lock()
+		released := w.released
+		w.mu.Unlock()
+		if released && d.stop(universe, w) {
+			return
+		}
+
+		select {
+		case <-w.ready:
+		case <-idle.C:
+			if d.stop(universe, w) {
+				return
+			}
+		}
+	}
+}
+
+// stop removes an idle worker. It returns false if jobs were queued in the meantime and the worker shall keep running.
+func (d *dispatcher) stop(universe uint16, w *dispatchWorker) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.jobs) > 0 {
+		return false
+	}
+	w.stopped = true
+	w.room.Broadcast()
+	if d.workers[universe] == w {
+		delete(d.workers, universe)
+	}
+	return true
+}
+
+// close stops all workers once they have run their queued callbacks.
+func (d *dispatcher) close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return
+	}
+	d.closed = true
+	for _, w := range d.workers {
+		w.mu.Lock()
+		w.released = true
+		w.mu.Unlock()
+		w.signal()
+	}
+}
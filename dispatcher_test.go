@@ -0,0 +1,197 @@
+package sacn
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDispatcherOrder(t *testing.T) {
+	d := newDispatcher(4, DispatchBlock)
+
+	var wg sync.WaitGroup
+	results := make(map[uint16][]int)
+	var mu sync.Mutex
+
+	for i := 0; i < 100; i++ {
+		for _, universe := range []uint16{1, 2, 3} {
+			i, universe := i, universe
+			wg.Add(1)
+			d.dispatch(universe, func() {
+				defer wg.Done()
+				mu.Lock()
+				results[universe] = append(results[universe], i)
+				mu.Unlock()
+			})
+		}
+	}
+	wg.Wait()
+	d.close()
+
+	for universe, values := range results {
+		if len(values) != 100 {
+			t.Fatalf("Missing callbacks on universe %d: %d != 100", universe, len(values))
+		}
+		for i, v := range values {
+			if i != v {
+				t.Fatalf("Callbacks out of order on universe %d: %v", universe, values)
+			}
+		}
+	}
+}
+
+func TestDispatcherPolicies(t *testing.T) {
+	tests := []struct {
+		policy  DispatchPolicy
+		last    int
+		dropped uint64
+	}{
+		{
+			policy:  DispatchDropOldest,
+			last:    9,
+			dropped: 9,
+		},
+		{
+			policy:  DispatchDropNewest,
+			last:    0,
+			dropped: 9,
+		},
+	}
+
+	for _, tt := range tests {
+		d := newDispatcher(1, tt.policy)
+
+		// Block the worker until all jobs are queued
+		started := make(chan bool)
+		release := make(chan bool)
+		d.dispatch(1, func() {
+			close(started)
+			<-release
+		})
+		<-started
+
+		done := make(chan int, 10)
+		for i := 0; i < 10; i++ {
+			i := i
+			d.dispatch(1, func() { done <- i })
+		}
+		close(release)
+
+		if got := <-done; got != tt.last {
+			t.Fatalf("Unexpected callback run with policy %v: %d != %d", tt.policy, got, tt.last)
+		}
+		if got := d.dropped.Load(); got != tt.dropped {
+			t.Fatalf("Unexpected dropped count with policy %v: %d != %d", tt.policy, got, tt.dropped)
+		}
+		d.close()
+	}
+}
+
+func TestDispatcherControl(t *testing.T) {
+	d := newDispatcher(1, DispatchDropOldest)
+	defer d.close()
+
+	// Block the worker until all jobs are queued
+	started := make(chan bool)
+	release := make(chan bool)
+	d.dispatch(1, func() {
+		close(started)
+		<-release
+	})
+	<-started
+
+	done := make(chan string, 10)
+	d.dispatchControl(1, func() { done <- "source event" })
+	for i := 0; i < 5; i++ {
+		d.dispatch(1, func() { done <- "packet" })
+	}
+	d.dispatchControl(1, func() { done <- "termination" })
+	close(release)
+
+	expected := []string{"source event", "packet", "termination"}
+	for _, want := range expected {
+		if got := <-done; got != want {
+			t.Fatalf("unexpected callback:\n- want: %v\n-  got: %v", want, got)
+		}
+	}
+	if got := d.dropped.Load(); got != 4 {
+		t.Fatalf("Unexpected dropped count: %d != 4", got)
+	}
+}
+
+func TestDispatcherAfter(t *testing.T) {
+	d := newDispatcher(0, DispatchDropOldest)
+	defer d.close()
+
+	var mu sync.Mutex
+	var order []uint16
+	record := func(universe uint16) {
+		mu.Lock()
+		order = append(order, universe)
+		mu.Unlock()
+	}
+
+	// The data of the members is still being processed when the sync packet arrives
+	for _, universe := range []uint16{1, 2} {
+		universe := universe
+		d.dispatch(universe, func() {
+			time.Sleep(50 * time.Millisecond)
+			record(universe)
+		})
+	}
+	done := make(chan struct{})
+	d.dispatchAfter(10, []uint16{1, 2, 10}, func() {
+		record(10)
+		close(done)
+	})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("sync callback not run")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(order) != 3 || order[2] != 10 {
+		t.Fatalf("unexpected order of callbacks:\n- want: sync universe 10 last\n-  got: %v", order)
+	}
+}
+
+func TestDispatcherWorkerLifetime(t *testing.T) {
+	d := newDispatcher(4, DispatchBlock)
+	d.idle = 50 * time.Millisecond
+	defer d.close()
+
+	workers := func() int {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return len(d.workers)
+	}
+	waitWorkers := func(want int, reason string) {
+		deadline := time.Now().Add(time.Second)
+		for workers() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("unexpected number of workers %s:\n- want: %d\n-  got: %d", reason, want, workers())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	done := make(chan bool, 2)
+	d.dispatch(1, func() { done <- true })
+	d.dispatch(2, func() { done <- true })
+	<-done
+	<-done
+	if got := workers(); got != 2 {
+		t.Fatalf("unexpected number of workers:\n- want: 2\n-  got: %d", got)
+	}
+
+	d.dispatch(2, func() {})
+	d.release(1)
+	waitWorkers(1, "after releasing a terminated universe")
+	waitWorkers(0, "after the idle timeout")
+
+	// A new worker is started for the next callback of the universe
+	d.dispatch(1, func() { done <- true })
+	<-done
+}
@@ -0,0 +1,55 @@
+package sacn
+
+import (
+	"errors"
+	"fmt"
+
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+// Errors returned by the [Sender] and [Receiver]. Use [errors.Is] to check them,
+// errors about a specific universe or sync group are wrapped in a [UniverseError].
+var (
+	ErrUniverseNotFound       = errors.New("Universe is not initialised, please use StartUniverse() first")
+	ErrUniverseAlreadyStarted = errors.New("Universe is already enabled")
+	ErrInvalidUniverse        = errors.New("Universe value is incorrect, should be between 1 and 63999")
+	ErrSyncGroupNotFound      = errors.New("Sync group is not initialised, please use StartSyncGroup() first")
+	ErrSyncGroupAlreadyExists = errors.New("Sync group is already started")
+	ErrNotSyncGroupMember     = errors.New("Universe is not a member of the sync group")
+	ErrMissingPacket          = errors.New("No packet to send on the universe")
+
+	ErrSlotOutOfRange     = errors.New("Slot is out of range, should be between 1 and 512")
+	ErrInvalidPriority    = errors.New("Priority value is incorrect, should be between 0 and 200")
+	ErrInvalidSyncAddress = errors.New("Sync address value is incorrect, should be between 0 and 63999")
+	ErrSourceNameTooLong  = packet.ErrSourceNameTooLong
+
+	ErrInvalidFrameRate         = errors.New("Frame rate cannot be negative")
+	ErrInvalidFadeDuration      = errors.New("Fade duration cannot be negative")
+	ErrInvalidDiscoveryInterval = errors.New("Discovery interval cannot be negative")
+
+	ErrInvalidMulticastTTL = errors.New("Multicast TTL is incorrect, should be between 0 and 255")
+	ErrInvalidDSCP         = errors.New("DSCP value is incorrect, should be between 0 and 63")
+	ErrInvalidBufferSize   = errors.New("Socket buffer size cannot be negative")
+
+	ErrSenderClosed = errors.New("Sender is closed")
+	ErrCIDInUse     = errors.New("CID is already used by the sender or one of its sources")
+)
+
+// UniverseError is returned by operations which failed on a universe (or the sync universe of a sync group).
+// Use [errors.As] to retrieve it and [errors.Is] to check the kind of error it wraps.
+type UniverseError struct {
+	Universe uint16
+	Err      error // The kind of error, one of the errors of this package.
+}
+
+func (e *UniverseError) Error() string {
+	return fmt.Sprintf("Universe %d: %v", e.Universe, e.Err)
+}
+
+func (e *UniverseError) Unwrap() error {
+	return e.Err
+}
+
+func universeNotFound(universe uint16) error {
+	return &UniverseError{Universe: universe, Err: ErrUniverseNotFound}
+}
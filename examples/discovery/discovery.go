@@ -13,7 +13,7 @@ func main() {
 	fmt.Println("hello")
 
 	itf, _ := net.InterfaceByName("en0") // specific to your machine
-	receiver, err := sacn.NewReceiver(itf)
+	receiver, err := sacn.NewReceiver(itf, nil)
 	if err != nil {
 		panic(err)
 	}
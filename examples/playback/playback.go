@@ -0,0 +1,55 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"gitlab.com/patopest/go-sacn"
+	"gitlab.com/patopest/go-sacn/playback"
+)
+
+func main() {
+	log.Println("Hello")
+
+	sender, err := sacn.NewSender("192.168.1.200", &sacn.SenderOptions{MaxFrameRate: sacn.DMX_FRAME_RATE}) // Create sender
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, uni := range []uint16{1, 2} {
+		sender.StartUniverse(uni)
+		sender.SetMulticast(uni, true)
+	}
+
+	cues := []playback.Cue{
+		{
+			Name:   "Warm",
+			Look:   playback.Look{1: {255, 180, 100}, 2: {255}},
+			FadeIn: 3 * time.Second,
+			Curve:  sacn.FadeSCurve,
+		},
+		{
+			Name:   "Cold",
+			Look:   playback.Look{1: {100, 180, 255}},
+			FadeIn: 5 * time.Second,
+			Curve:  sacn.FadeLinear,
+			Follow: 10 * time.Second, // automatically go to the next cue
+		},
+		{
+			Name:  "Blackout",
+			Look:  playback.Look{1: {0, 0, 0}, 2: {0}},
+			Delay: 1 * time.Second,
+			Curve: sacn.FadeSnap,
+		},
+	}
+
+	player := playback.NewPlayer(sender, cues, nil)
+	player.Start()
+
+	player.Go() // Warm
+	time.Sleep(5 * time.Second)
+	player.Go() // Cold, followed by Blackout
+	time.Sleep(15 * time.Second)
+
+	player.Stop()
+	sender.Close()
+}
@@ -13,13 +13,14 @@ func main() {
 	fmt.Println("hello")
 
 	itf, _ := net.InterfaceByName("en0") // specific to your machine
-	receiver, err := sacn.NewReceiver(itf)
+	receiver, err := sacn.NewReceiver(itf, nil)
 	if err != nil {
 		panic(err)
 	}
 	receiver.JoinUniverse(1)
 	receiver.RegisterPacketCallback(packet.PacketTypeData, dataPacketCallback)
 	receiver.RegisterTerminationCallback(universeTerminatedCallback)
+	receiver.RegisterSourceEventCallback(sourceEventCallback)
 	receiver.Start()
 
 	for {
@@ -38,3 +39,7 @@ func dataPacketCallback(p packet.SACNPacket, info sacn.PacketInfo) {
 func universeTerminatedCallback(universe uint16) {
 	fmt.Printf("Universe %d is terminated\n", universe)
 }
+
+func sourceEventCallback(event sacn.SourceEvent) {
+	fmt.Printf("Source \"%s\" (%s) %s on universe %d\n", event.SourceName, event.Source.IP.String(), event.Type, event.Universe)
+}
@@ -0,0 +1,192 @@
+package sacn
+
+import (
+	"time"
+)
+
+// FadeCurve defines how slot values move from their start to their target value during a fade.
+type FadeCurve int
+
+// Possible curves for [Sender.Fade].
+const (
+	FadeLinear FadeCurve = iota // Constant speed.
+	FadeSCurve                  // Slow start and end, fastest in the middle.
+	FadeSnap                    // Jump to the target value on the first frame.
+)
+
+// Apply returns the progress of the curve (0 to 1) at the fraction t (0 to 1) of the fade duration.
+func (c FadeCurve) Apply(t float64) float64 {
+	if c == FadeSnap {
+		return 1
+	}
+	if t <= 0 {
+		return 0
+	}
+	if t >= 1 {
+		return 1
+	}
+	switch c {
+	case FadeSCurve:
+		return t * t * (3 - 2*t) // smoothstep
+	default:
+		return t
+	}
+}
+
+// Interpolate returns the value between from and to at the fraction t (0 to 1) of a fade using the curve.
+func (c FadeCurve) Interpolate(from byte, to byte, t float64) byte {
+	value := float64(from) + (float64(to)-float64(from))*c.Apply(t)
+	return byte(value + 0.5)
+}
+
+// State of the fade of a single slot
+type slotFade struct {
+	active   bool
+	from     byte
+	to       byte
+	start    time.Time
+	duration time.Duration
+	curve    FadeCurve
+}
+
+func (f *slotFade) value(now time.Time) byte {
+	if f.duration <= 0 {
+		return f.curve.Interpolate(f.from, f.to, 1)
+	}
+	t := float64(now.Sub(f.start)) / float64(f.duration)
+	return f.curve.Interpolate(f.from, f.to, t)
+}
+
+func (f *slotFade) done(now time.Time) bool {
+	return f.curve == FadeSnap || now.Sub(f.start) >= f.duration
+}
+
+// Fade moves slots of a universe, starting at slot start (1 to 512), to the target values over the duration following the curve.
+//
+// Fades are computed on every frame sent by the universe, at its maximum frame rate or [DMX_FRAME_RATE] if it is unlimited.
+// Fading a slot which is already fading retargets it: the new fade starts from its current value.
+// Setting a slot with [Sender.SetSlot] or its variants interrupts its fade.
+func (s *Sender) Fade(universe uint16, start int, targets []byte, duration time.Duration, curve FadeCurve) error {
+	if start < 1 || start+len(targets)-1 > 512 {
+		return ErrSlotOutOfRange
+	}
+	if duration < 0 {
+		return ErrInvalidFadeDuration
+	}
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		uni.startFade(start, targets, duration, curve, time.Now())
+		uni.mu.Unlock()
+		uni.wake()
+		return nil
+	}
+	return universeNotFound(universe)
+}
+
+// FadeUniverse moves the slots of a universe to the target values, starting at slot 1. See [Sender.Fade].
+func (s *Sender) FadeUniverse(universe uint16, targets []byte, duration time.Duration, curve FadeCurve) error {
+	return s.Fade(universe, 1, targets, duration, curve)
+}
+
+// StopFades interrupts all the fades of a universe, leaving its slots at their current values.
+func (s *Sender) StopFades(universe uint16) error {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		uni.stopFades(time.Now())
+		uni.mu.Unlock()
+		uni.wake()
+		return nil
+	}
+	return universeNotFound(universe)
+}
+
+// IsFading returns true if any slot of the universe is fading.
+func (s *Sender) IsFading(universe uint16) (bool, error) {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		defer uni.mu.Unlock()
+		return uni.fading > 0, nil
+	}
+	return false, universeNotFound(universe)
+}
+
+// The caller shall hold the universe's mu.
+func (uni *senderUniverse) startFade(start int, targets []byte, duration time.Duration, curve FadeCurve, now time.Time) {
+	for i, target := range targets {
+		slot := start - 1 + i
+		f := &uni.fades[slot]
+		from := uni.levels[slot]
+		if f.active {
+			from = f.value(now) // retarget from the current value
+		} else {
+			uni.fading += 1
+		}
+		*f = slotFade{
+			active:   true,
+			from:     from,
+			to:       target,
+			start:    now,
+			duration: duration,
+			curve:    curve,
+		}
+	}
+}
+
+// The caller shall hold the universe's mu.
+func (uni *senderUniverse) stopFades(now time.Time) {
+	for slot := range uni.fades {
+		f := &uni.fades[slot]
+		if f.active {
+			uni.levels[slot] = f.value(now)
+			f.active = false
+			uni.levelsChanged = true
+		}
+	}
+	uni.fading = 0
+}
+
+// The caller shall hold the universe's mu.
+func (uni *senderUniverse) cancelFades(start int, length int) {
+	for slot := start - 1; slot < start-1+length; slot++ {
+		if uni.fades[slot].active {
+			uni.fades[slot].active = false
+			uni.fading -= 1
+		}
+	}
+}
+
+// stepFades computes the value of all fading slots for the frame sent at now.
+// It returns true while some slots are still fading.
+func (uni *senderUniverse) stepFades(now time.Time) bool {
+	uni.mu.Lock()
+	defer uni.mu.Unlock()
+
+	if uni.fading == 0 {
+		return false
+	}
+	for slot := range uni.fades {
+		f := &uni.fades[slot]
+		if !f.active {
+			continue
+		}
+		uni.levels[slot] = f.value(now)
+		if f.done(now) {
+			uni.levels[slot] = f.to
+			f.active = false
+			uni.fading -= 1
+		}
+	}
+	uni.levelsChanged = true
+	return uni.fading > 0
+}
+
+// Interval of the fade output clock of the universe
+func (uni *senderUniverse) fadeInterval() time.Duration {
+	if interval := uni.frameInterval(); interval > 0 {
+		return interval
+	}
+	return time.Second / DMX_FRAME_RATE
+}
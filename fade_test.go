@@ -0,0 +1,104 @@
+package sacn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFadeCurve(t *testing.T) {
+	tests := []struct {
+		curve    FadeCurve
+		t        float64
+		expected byte
+	}{
+		{curve: FadeLinear, t: -1, expected: 0},
+		{curve: FadeLinear, t: 0, expected: 0},
+		{curve: FadeLinear, t: 0.5, expected: 128},
+		{curve: FadeLinear, t: 1, expected: 255},
+		{curve: FadeLinear, t: 2, expected: 255},
+		{curve: FadeSCurve, t: 0.1, expected: 7},
+		{curve: FadeSCurve, t: 0.5, expected: 128},
+		{curve: FadeSCurve, t: 0.9, expected: 248},
+		{curve: FadeSnap, t: 0, expected: 255},
+	}
+
+	for _, tt := range tests {
+		value := tt.curve.Interpolate(0, 255, tt.t)
+		if value != tt.expected {
+			t.Fatalf("Unexpected value for curve %d at %v: %d != %d", tt.curve, tt.t, value, tt.expected)
+		}
+	}
+
+	if value := FadeLinear.Interpolate(200, 100, 0.25); value != 175 {
+		t.Fatalf("Unexpected value for fade down: %d != 175", value)
+	}
+}
+
+func TestFadeSteps(t *testing.T) {
+	uni := &senderUniverse{}
+	now := time.Now()
+
+	uni.levels[0] = 100
+	uni.startFade(1, []byte{200, 50}, time.Second, FadeLinear, now)
+
+	tests := []struct {
+		name     string
+		at       time.Duration
+		update   func(at time.Time)
+		expected []byte
+		fading   bool
+	}{
+		{
+			name:     "Start",
+			expected: []byte{100, 0},
+			fading:   true,
+		},
+		{
+			name:     "Half way",
+			at:       500 * time.Millisecond,
+			expected: []byte{150, 25},
+			fading:   true,
+		},
+		{
+			name: "Retarget",
+			at:   500 * time.Millisecond,
+			update: func(at time.Time) {
+				uni.startFade(1, []byte{0}, time.Second, FadeLinear, at)
+			},
+			expected: []byte{150, 25},
+			fading:   true,
+		},
+		{
+			name:     "Original end",
+			at:       1000 * time.Millisecond,
+			expected: []byte{75, 50},
+			fading:   true,
+		},
+		{
+			name: "Interrupted",
+			at:   1100 * time.Millisecond,
+			update: func(at time.Time) {
+				uni.cancelFades(1, 1)
+				uni.levels[0] = 10
+			},
+			expected: []byte{10, 50},
+			fading:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		at := now.Add(tt.at)
+		if tt.update != nil {
+			tt.update(at)
+		}
+		fading := uni.stepFades(at)
+		if fading != tt.fading {
+			t.Fatalf("Unexpected fading state on \"%s\": %v != %v", tt.name, fading, tt.fading)
+		}
+		for i, value := range tt.expected {
+			if uni.levels[i] != value {
+				t.Fatalf("Unexpected levels on \"%s\": %v != %v", tt.name, uni.levels[:len(tt.expected)], tt.expected)
+			}
+		}
+	}
+}
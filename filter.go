@@ -0,0 +1,86 @@
+package sacn
+
+import (
+	"net"
+	"regexp"
+	"strings"
+
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+// SourceFilter accepts or rejects packets received by a [Receiver] based on their source.
+// Use [Receiver.SetFilter] and [Receiver.SetUniverseFilter] to apply it.
+//
+// A packet is rejected if it matches any of the Deny rules.
+// If any Allow rule is set, a packet is only accepted if it matches at least one of them.
+// Name rules only apply to sources whose name is known (from a [packet.DataPacket] or [packet.DiscoveryPacket]).
+type SourceFilter struct {
+	AllowCIDs  []packet.CID     // CIDs of sources to accept.
+	DenyCIDs   []packet.CID     // CIDs of sources to reject.
+	AllowNets  []*net.IPNet     // IP addresses or ranges of sources to accept. See [ParseSourceNet].
+	DenyNets   []*net.IPNet     // IP addresses or ranges of sources to reject. See [ParseSourceNet].
+	AllowNames []*regexp.Regexp // Patterns of source names to accept.
+	DenyNames  []*regexp.Regexp // Patterns of source names to reject.
+}
+
+// ParseSourceNet parses a single IP address (eg: "192.168.1.100") or a CIDR range (eg: "10.0.0.0/8") to be used in a [SourceFilter].
+func ParseSourceNet(s string) (*net.IPNet, error) {
+	if !strings.Contains(s, "/") {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, &net.ParseError{Type: "IP address", Text: s}
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+	_, ipnet, err := net.ParseCIDR(s)
+	return ipnet, err
+}
+
+func (f *SourceFilter) accepts(cid packet.CID, ip net.IP, name string) bool {
+	if f == nil {
+		return true
+	}
+
+	for _, c := range f.DenyCIDs {
+		if c == cid {
+			return false
+		}
+	}
+	for _, n := range f.DenyNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	if name != "" {
+		for _, re := range f.DenyNames {
+			if re.MatchString(name) {
+				return false
+			}
+		}
+	}
+
+	if len(f.AllowCIDs) == 0 && len(f.AllowNets) == 0 && len(f.AllowNames) == 0 {
+		return true
+	}
+	for _, c := range f.AllowCIDs {
+		if c == cid {
+			return true
+		}
+	}
+	for _, n := range f.AllowNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	if name != "" {
+		for _, re := range f.AllowNames {
+			if re.MatchString(name) {
+				return true
+			}
+		}
+	}
+	return false
+}
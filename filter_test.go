@@ -0,0 +1,164 @@
+package sacn
+
+import (
+	"net"
+	"regexp"
+	"testing"
+
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+func TestParseSourceNet(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+		err      bool
+	}{
+		{
+			value:    "192.168.1.100",
+			expected: "192.168.1.100/32",
+		},
+		{
+			value:    "10.0.0.0/8",
+			expected: "10.0.0.0/8",
+		},
+		{
+			value:    "fe80::1",
+			expected: "fe80::1/128",
+		},
+		{
+			value: "not an ip",
+			err:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		n, err := ParseSourceNet(tt.value)
+		if tt.err {
+			if err == nil {
+				t.Fatalf("No error returned for \"%s\"", tt.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error for \"%s\": %v", tt.value, err)
+		}
+		if n.String() != tt.expected {
+			t.Fatalf("Unexpected network for \"%s\": %s != %s", tt.value, n.String(), tt.expected)
+		}
+	}
+}
+
+func TestSourceFilter(t *testing.T) {
+	cidA := packet.CID{0x01}
+	cidB := packet.CID{0x02}
+	lan, _ := ParseSourceNet("192.168.1.0/24")
+	stray, _ := ParseSourceNet("192.168.1.66")
+
+	tests := []struct {
+		name     string
+		filter   *SourceFilter
+		cid      packet.CID
+		ip       string
+		source   string
+		expected bool
+	}{
+		{
+			name:     "No filter",
+			filter:   nil,
+			cid:      cidA,
+			ip:       "10.0.0.1",
+			expected: true,
+		},
+		{
+			name:     "Denied CID",
+			filter:   &SourceFilter{DenyCIDs: []packet.CID{cidA}},
+			cid:      cidA,
+			ip:       "10.0.0.1",
+			expected: false,
+		},
+		{
+			name:     "Allowed CID",
+			filter:   &SourceFilter{AllowCIDs: []packet.CID{cidA}},
+			cid:      cidB,
+			ip:       "10.0.0.1",
+			expected: false,
+		},
+		{
+			name:     "Allowed range",
+			filter:   &SourceFilter{AllowNets: []*net.IPNet{lan}},
+			cid:      cidB,
+			ip:       "192.168.1.10",
+			expected: true,
+		},
+		{
+			name:     "Denied address in allowed range",
+			filter:   &SourceFilter{AllowNets: []*net.IPNet{lan}, DenyNets: []*net.IPNet{stray}},
+			cid:      cidB,
+			ip:       "192.168.1.66",
+			expected: false,
+		},
+		{
+			name:     "Allowed name",
+			filter:   &SourceFilter{AllowNames: []*regexp.Regexp{regexp.MustCompile("^Main console")}},
+			cid:      cidB,
+			ip:       "10.0.0.1",
+			source:   "Main console (backup)",
+			expected: true,
+		},
+		{
+			name:     "Denied name",
+			filter:   &SourceFilter{DenyNames: []*regexp.Regexp{regexp.MustCompile("(?i)visualiser")}},
+			cid:      cidB,
+			ip:       "10.0.0.1",
+			source:   "Office Visualiser",
+			expected: false,
+		},
+		{
+			name:     "Unknown name",
+			filter:   &SourceFilter{AllowNames: []*regexp.Regexp{regexp.MustCompile(".*")}},
+			cid:      cidB,
+			ip:       "10.0.0.1",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		value := tt.filter.accepts(tt.cid, net.ParseIP(tt.ip), tt.source)
+		if value != tt.expected {
+			t.Fatalf("Unexpected filter result on \"%s\": %v != %v", tt.name, value, tt.expected)
+		}
+	}
+}
+
+func TestReceiverSourceNames(t *testing.T) {
+	r := newTestReceiver(t)
+	r.SetFilter(&SourceFilter{DenyNames: []*regexp.Regexp{regexp.MustCompile("Spoofed")}})
+
+	info := PacketInfo{Source: net.UDPAddr{IP: net.ParseIP("10.0.0.1")}}
+	for i := 0; i < 100; i++ {
+		p := packet.NewDataPacket()
+		p.CID = packet.CID{0xFF, byte(i)}
+		p.Universe = 1
+		p.SetSourceName("Spoofed")
+		r.handlePacket(p, info)
+	}
+	if len(r.sourceNames) != 0 {
+		t.Fatalf("unexpected source names stored for rejected sources: %d", len(r.sourceNames))
+	}
+
+	p := packet.NewDataPacket()
+	p.CID = packet.CID{0x01}
+	p.Universe = 1
+	p.SetSourceName("Console")
+	r.handlePacket(p, info)
+	if want, got := "Console", r.sourceNames[p.CID]; want != got {
+		t.Fatalf("unexpected source name of accepted source:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	p.SetStreamTerminated(true)
+	r.handlePacket(p, info)
+	if name, exists := r.sourceNames[p.CID]; exists {
+		t.Fatalf("source name kept after the source terminated: %v", name)
+	}
+}
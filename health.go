@@ -0,0 +1,151 @@
+package sacn
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+// SendError is reported by a [Sender] when a packet could not be sent. See [Sender.RegisterErrorCallback].
+type SendError struct {
+	Universe    uint16                // Universe the packet was sent on (the sync address for SyncPackets).
+	Destination net.UDPAddr           // Multicast or unicast address the packet was sent to. Empty if the packet could not be marshalled.
+	PacketType  packet.SACNPacketType // Type of the packet.
+	Err         error                 // Underlying error.
+}
+
+func (e *SendError) Error() string {
+	if e.Destination.IP == nil {
+		return fmt.Sprintf("Error sending packet on universe %d: %v", e.Universe, e.Err)
+	}
+	return fmt.Sprintf("Error sending packet on universe %d to %s: %v", e.Universe, e.Destination.IP, e.Err)
+}
+
+func (e *SendError) Unwrap() error {
+	return e.Err
+}
+
+// SendErrorCallbackFunc is the function type to be used with [Sender.RegisterErrorCallback].
+type SendErrorCallbackFunc func(err *SendError)
+
+// DestinationHealth is the state of a destination packets are sent to.
+type DestinationHealth int
+
+// Possible states of a destination.
+const (
+	DestinationHealthy DestinationHealth = iota // The last packet sent to the destination succeeded.
+	DestinationFailing                          // The last packet sent to the destination failed.
+)
+
+func (h DestinationHealth) String() string {
+	switch h {
+	case DestinationHealthy:
+		return "healthy"
+	case DestinationFailing:
+		return "failing"
+	default:
+		return fmt.Sprintf("DestinationHealth(%d)", int(h))
+	}
+}
+
+// DestinationStatus is the health of a destination returned by [Sender.GetDestinationHealth].
+type DestinationStatus struct {
+	Health    DestinationHealth
+	Since     time.Time // When the destination entered its current health state. Zero if nothing was sent to it yet.
+	Failures  uint64    // Number of consecutive failed packets.
+	LastError error     // Last error sending to the destination, kept after it recovers.
+}
+
+// RegisterErrorCallback registers a callback for packets which could not be marshalled or sent to one of their destinations.
+// The callback is called from the goroutine sending the packet, it should return quickly to not delay the universe.
+func (s *Sender) RegisterErrorCallback(callback SendErrorCallbackFunc) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	s.errorCallback = callback
+}
+
+// GetDestinationHealth returns the health of a multicast or unicast destination (eg: "192.168.1.100").
+// Destinations nothing was sent to yet are reported as healthy.
+func (s *Sender) GetDestinationHealth(destination string) DestinationStatus {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	if status, exists := s.health[destination]; exists {
+		return *status
+	}
+	return DestinationStatus{Health: DestinationHealthy}
+}
+
+// GetDestinationsHealth returns the health of all the destinations packets were sent to, keyed by their IP address.
+func (s *Sender) GetDestinationsHealth() map[string]DestinationStatus {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	health := make(map[string]DestinationStatus, len(s.health))
+	for dest, status := range s.health {
+		health[dest] = *status
+	}
+	return health
+}
+
+// reportSend updates the health of the destination after sending a packet to it, and reports the error if any.
+// Errors are passed to the error callback every time, but only logged when the destination starts failing or recovers.
+func (s *Sender) reportSend(universe uint16, dest *net.UDPAddr, p packet.SACNPacket, err error) {
+	changed := dest == nil // marshalling errors are always logged
+	var failures uint64
+	s.healthMu.Lock()
+	if dest != nil {
+		key := dest.IP.String()
+		status, exists := s.health[key]
+		if !exists {
+			status = &DestinationStatus{Health: DestinationHealthy, Since: time.Now()}
+			s.health[key] = status
+		}
+		if err == nil {
+			if status.Health != DestinationHealthy {
+				status.Health = DestinationHealthy
+				status.Since = time.Now()
+				changed = true
+				failures = status.Failures
+			}
+			status.Failures = 0
+		} else {
+			if status.Health != DestinationFailing {
+				status.Health = DestinationFailing
+				status.Since = time.Now()
+				changed = true
+			}
+			status.Failures += 1
+			status.LastError = err
+		}
+	}
+	callback := s.errorCallback
+	s.healthMu.Unlock()
+
+	if err == nil {
+		if changed {
+			s.logger.Info("Destination recovered",
+				slog.String("destination", dest.IP.String()),
+				slog.Uint64("failures", failures),
+			)
+		}
+		return
+	}
+	sendErr := &SendError{Universe: universe, PacketType: p.GetType(), Err: err}
+	if dest != nil {
+		sendErr.Destination = *dest
+	}
+	if changed {
+		s.logger.Warn("Could not send packet",
+			slog.Int("universe", int(universe)),
+			slog.String("cid", packetCID(p).String()),
+			slog.String("packet_type", packetTypeName(sendErr.PacketType)),
+			slog.String("destination", sendErr.Destination.IP.String()),
+			slog.Any("error", err),
+		)
+	}
+	if callback != nil {
+		callback(sendErr)
+	}
+}
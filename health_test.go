@@ -0,0 +1,63 @@
+package sacn
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+func TestDestinationHealth(t *testing.T) {
+	var logs bytes.Buffer
+	sender, err := NewSender("127.0.0.1", &SenderOptions{Logger: slog.New(slog.NewTextHandler(&logs, nil)), DiscoveryInterval: -1})
+	if err != nil {
+		t.Fatalf("Failed to create sender: %v", err)
+	}
+	defer sender.Close()
+
+	reported := make([]*SendError, 0)
+	sender.RegisterErrorCallback(func(err *SendError) {
+		reported = append(reported, err)
+	})
+
+	dest := &net.UDPAddr{IP: net.ParseIP("192.168.1.100"), Port: SACN_PORT}
+	unreachable := errors.New("network is unreachable")
+
+	tests := []struct {
+		name     string
+		err      error
+		health   DestinationHealth
+		failures uint64
+		logs     int // log records so far, only changes of health are logged
+	}{
+		{name: "Sent", err: nil, health: DestinationHealthy, failures: 0, logs: 0},
+		{name: "First failure", err: unreachable, health: DestinationFailing, failures: 1, logs: 1},
+		{name: "Second failure", err: unreachable, health: DestinationFailing, failures: 2, logs: 1},
+		{name: "Recovered", err: nil, health: DestinationHealthy, failures: 0, logs: 2},
+	}
+
+	for _, tt := range tests {
+		sender.reportSend(1, dest, packet.NewDataPacket(), tt.err)
+		status := sender.GetDestinationHealth("192.168.1.100")
+		if status.Health != tt.health || status.Failures != tt.failures {
+			t.Fatalf("unexpected health on \"%s\":\n- want: %v (%d failures)\n-  got: %v (%d failures)", tt.name, tt.health, tt.failures, status.Health, status.Failures)
+		}
+		if got := strings.Count(logs.String(), "\n"); got != tt.logs {
+			t.Fatalf("unexpected number of log records on \"%s\":\n- want: %d\n-  got: %d\n%s", tt.name, tt.logs, got, logs.String())
+		}
+	}
+
+	if len(reported) != 2 {
+		t.Fatalf("unexpected number of reported errors:\n- want: %d\n-  got: %d", 2, len(reported))
+	}
+	if !errors.Is(reported[0], unreachable) || reported[0].Universe != 1 || !reported[0].Destination.IP.Equal(dest.IP) {
+		t.Fatalf("unexpected reported error: %v", reported[0])
+	}
+	if status := sender.GetDestinationHealth("192.168.1.200"); status.Health != DestinationHealthy {
+		t.Fatalf("unexpected health of unknown destination: %v", status.Health)
+	}
+}
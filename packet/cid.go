@@ -0,0 +1,43 @@
+package packet
+
+import (
+	"github.com/google/uuid"
+)
+
+// A CID (Component Identifier) is the RFC 4122 UUID identifying a sACN source. Defined in Section 5.6 of ANSI E1.31—2018.
+// It should stay the same across restarts of a device.
+type CID [16]byte
+
+// ParseCID parses a CID in the UUID text form (eg: "ef07c8dd-0064-4401-a3a2-459ef8e6143e").
+func ParseCID(s string) (CID, error) {
+	u, err := uuid.Parse(s)
+	if err != nil {
+		return CID{}, err
+	}
+	return CID(u), nil
+}
+
+// String returns the CID in the UUID text form.
+func (c CID) String() string {
+	return uuid.UUID(c).String()
+}
+
+// IsZero returns true if the CID is not set.
+func (c CID) IsZero() bool {
+	return c == CID{}
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+func (c CID) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+func (c *CID) UnmarshalText(b []byte) error {
+	cid, err := ParseCID(string(b))
+	if err != nil {
+		return err
+	}
+	*c = cid
+	return nil
+}
@@ -0,0 +1,55 @@
+package packet
+
+import (
+	"testing"
+)
+
+func TestCID(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		expected CID
+		err      bool
+	}{
+		{
+			name:     "Canonical",
+			s:        "ef07c8dd-0064-4401-a3a2-459ef8e6143e",
+			expected: CID{0xef, 0x07, 0xc8, 0xdd, 0x00, 0x64, 0x44, 0x01, 0xa3, 0xa2, 0x45, 0x9e, 0xf8, 0xe6, 0x14, 0x3e},
+		},
+		{
+			name:     "Upper case",
+			s:        "EF07C8DD-0064-4401-A3A2-459EF8E6143E",
+			expected: CID{0xef, 0x07, 0xc8, 0xdd, 0x00, 0x64, 0x44, 0x01, 0xa3, 0xa2, 0x45, 0x9e, 0xf8, 0xe6, 0x14, 0x3e},
+		},
+		{
+			name: "Too short",
+			s:    "ef07c8dd-0064-4401-a3a2",
+			err:  true,
+		},
+		{
+			name: "Not hexadecimal",
+			s:    "zz07c8dd-0064-4401-a3a2-459ef8e6143e",
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		cid, err := ParseCID(tt.s)
+		if (err != nil) != tt.err {
+			t.Fatalf("unexpected error on \"%s\": %v", tt.name, err)
+		}
+		if err != nil {
+			continue
+		}
+		if cid != tt.expected {
+			t.Fatalf("unexpected CID on \"%s\":\n- want: %v\n-  got: %v", tt.name, tt.expected, cid)
+		}
+		if s := cid.String(); s != "ef07c8dd-0064-4401-a3a2-459ef8e6143e" {
+			t.Fatalf("unexpected string on \"%s\": %s", tt.name, s)
+		}
+	}
+
+	if !(CID{}).IsZero() {
+		t.Fatalf("zero CID is not zero")
+	}
+}
@@ -4,8 +4,7 @@ import (
 	"bytes"
 	"encoding"
 	"encoding/binary"
-	"errors"
-	// "fmt"
+	"fmt"
 )
 
 // Constants defined in ANSI E1.31—2018 Appendix A.
@@ -21,6 +20,19 @@ const (
 	VECTOR_UNIVERSE_DISCOVERY_UNIVERSE_LIST = 0x0001
 )
 
+// DMX512-A Start Codes used in [DataPacket]. See ANSI E1.11 and the ESTA alternate start code registry.
+const (
+	START_CODE_NULL                 = 0x00 // Dimmer levels
+	START_CODE_PER_ADDRESS_PRIORITY = 0xDD // Per-address priorities (de-facto standard), each slot is the priority of the same slot of level data
+)
+
+// Size in bytes of the largest [SACNPacket]: a [DiscoveryPacket] with 512 universes.
+// A buffer of this size can hold any packet marshalled with MarshalTo.
+const MAX_PACKET_SIZE = discoveryHeaderSize + 512*2
+
+// Default priority of a [DataPacket]. Valid priorities range from 0 to 200. See Section 6.2.3 of ANSI E1.31—2018
+const DEFAULT_PRIORITY = 100
+
 var packetIdentifierE117 = [12]byte{0x41, 0x53, 0x43, 0x2d, 0x45, 0x31, 0x2e, 0x31, 0x37, 0x00, 0x00, 0x00}
 
 // The [SACNPacket] type return by GetType of [SACNPacket]
@@ -37,6 +49,8 @@ const (
 type SACNPacket interface {
 	encoding.BinaryUnmarshaler
 	encoding.BinaryMarshaler
+	MarshalTo(b []byte) (int, error)
+	Size() int
 	validate() error
 	GetType() SACNPacketType
 }
@@ -48,12 +62,12 @@ type RootLayer struct {
 	ACNPacketIdentifier [12]byte
 	RootLength          uint16
 	RootVector          uint32
-	CID                 [16]byte
+	CID                 CID
 }
 
 func (r *RootLayer) unmarshal(b []byte) error {
 	if len(b) < 38 {
-		return errors.New("Root layer length incorrect")
+		return &FieldError{Field: "Size", Offset: 0, Value: len(b), Err: ErrInvalidLength}
 	}
 
 	r.PreambleSize = binary.BigEndian.Uint16(b[0:2])
@@ -63,18 +77,65 @@ func (r *RootLayer) unmarshal(b []byte) error {
 	r.RootVector = binary.BigEndian.Uint32(b[18:22])
 	copy(r.CID[:16], b[22:38])
 
+	if err := checkPDULength(b, "RootLength", 16, r.RootLength, rootLayerSize-16); err != nil {
+		return err
+	}
 	return r.validate()
 }
 
+// Size in bytes of the RootLayer
+const rootLayerSize = 38
+
+// marshal writes the root layer to the start of b, which shall be at least [rootLayerSize] long.
+// size is the size of the whole packet, from which the RootLength is derived.
+func (r *RootLayer) marshal(b []byte, size int) {
+	binary.BigEndian.PutUint16(b[0:2], r.PreambleSize)
+	binary.BigEndian.PutUint16(b[2:4], r.PostambleSize)
+	copy(b[4:16], r.ACNPacketIdentifier[:])
+	binary.BigEndian.PutUint16(b[16:18], pduLength(size-16))
+	binary.BigEndian.PutUint32(b[18:22], r.RootVector)
+	copy(b[22:38], r.CID[:])
+}
+
 func (r *RootLayer) validate() error {
 	if r.PreambleSize != 0x0010 {
-		return errors.New("Incorrect Preamble size in Root Layer")
+		return &FieldError{Field: "PreambleSize", Offset: 0, Value: int(r.PreambleSize), Err: ErrInvalidRootLayer}
 	}
 	if r.PostambleSize != 0x0000 {
-		return errors.New("Incorrect Postamble size in Root Layer")
+		return &FieldError{Field: "PostambleSize", Offset: 2, Value: int(r.PostambleSize), Err: ErrInvalidRootLayer}
 	}
 	if !bytes.Equal(r.ACNPacketIdentifier[:], packetIdentifierE117[:]) {
-		return errors.New("Incorrect ACN Packet Identifier")
+		return &FieldError{Field: "ACNPacketIdentifier", Offset: 4, Value: 0, Err: ErrInvalidRootLayer}
+	}
+	return nil
+}
+
+// Returns the Flags and Length field of a PDU of the given length. See Section 5.4 of ANSI E1.31—2018
+func pduLength(length int) uint16 {
+	return 0x7000 | uint16(length)&0x0FFF
+}
+
+// Returns an error if the PDU whose length field is at offset in b is shorter than min or longer than b.
+func checkPDULength(b []byte, field string, offset int, length uint16, min int) error {
+	pduLength := int(length & 0x0FFF)
+	if pduLength < min || pduLength > len(b)-offset {
+		return &FieldError{Field: field, Offset: offset, Value: pduLength, Err: ErrInvalidLength}
+	}
+	return nil
+}
+
+// Returns an error if b is shorter than size
+func checkPacketSize(b []byte, size int) error {
+	if len(b) < size {
+		return &FieldError{Field: "Size", Offset: 0, Value: len(b), Err: ErrInvalidLength}
+	}
+	return nil
+}
+
+// Returns an error if a buffer of length available cannot hold a packet of the given size
+func checkBufferSize(available int, size int) error {
+	if available < size {
+		return fmt.Errorf("%w: %d < %d", ErrBufferTooSmall, available, size)
 	}
 	return nil
 }
@@ -87,7 +148,10 @@ func Unmarshal(b []byte) (p SACNPacket, err error) {
 		return
 	}
 
-	errUnhandled := errors.New("Unhandled packet type")
+	if err = checkPacketSize(b, rootLayerSize+6); err != nil { // Flags and Length, Vector of the framing layer
+		return nil, err
+	}
+
 	frameVector := binary.BigEndian.Uint32(b[40:44])
 
 	switch r.RootVector {
@@ -101,10 +165,10 @@ func Unmarshal(b []byte) (p SACNPacket, err error) {
 		case VECTOR_E131_EXTENDED_DISCOVERY:
 			p = &DiscoveryPacket{}
 		default:
-			return nil, errUnhandled
+			return nil, &FieldError{Field: "FrameVector", Offset: 40, Value: int(frameVector), Err: ErrUnhandledPacketType}
 		}
 	default:
-		return nil, errUnhandled
+		return nil, &FieldError{Field: "RootVector", Offset: 18, Value: int(r.RootVector), Err: ErrUnhandledPacketType}
 	}
 
 	err = p.UnmarshalBinary(b)
@@ -0,0 +1,105 @@
+package packet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// Returns a copy of b with the 2 bytes at offset set to value
+func withUint16(b []byte, offset int, value uint16) []byte {
+	c := bytes.Clone(b)
+	binary.BigEndian.PutUint16(c[offset:offset+2], value)
+	return c
+}
+
+func TestUnmarshalMalformed(t *testing.T) {
+	data := data_tests[1].b
+	sync := sync_tests[0].b[:]
+	discovery := discovery_tests[0].b
+
+	tests := []struct {
+		name  string
+		b     []byte
+		err   error
+		field string
+	}{
+		{name: "Empty packet", b: []byte{}, err: ErrInvalidLength, field: "Size"},
+		{name: "Root layer only", b: data[:38], err: ErrInvalidLength, field: "RootLength"},
+		{name: "Truncated framing layer", b: withUint16(data[:42], 16, 0x701A), err: ErrInvalidLength, field: "Size"},
+		{name: "Truncated data packet", b: data[:100], err: ErrInvalidLength, field: "RootLength"},
+		{name: "Short data packet", b: withUint16(data[:100], 16, 0x7054), err: ErrInvalidLength, field: "Size"},
+		{name: "Truncated data", b: data[:len(data)-1], err: ErrInvalidLength, field: "RootLength"},
+		{name: "Root length too long", b: withUint16(data, 16, 0x7FFF), err: ErrInvalidLength, field: "RootLength"},
+		{name: "Frame length too long", b: withUint16(data, 38, 0x7FFF), err: ErrInvalidLength, field: "FrameLength"},
+		{name: "DMP length too long", b: withUint16(data, 115, 0x7FFF), err: ErrInvalidLength, field: "DMPLength"},
+		{name: "Property value count too long", b: withUint16(data, 123, 0x0FFF), err: ErrInvalidLength, field: "Length"},
+		{name: "Property value count over 513", b: withUint16(append(bytes.Clone(data_tests[0].b), 0x00), 123, 514), err: ErrInvalidLength, field: "Length"},
+		{name: "Invalid preamble", b: withUint16(data, 0, 0x0020), err: ErrInvalidRootLayer, field: "PreambleSize"},
+		{name: "Unhandled root vector", b: withUint16(data, 20, 0x0005), err: ErrUnhandledPacketType, field: "RootVector"},
+		{name: "Invalid frame vector", b: withUint16(data, 42, 0x0003), err: ErrInvalidFrameVector, field: "FrameVector"},
+		{name: "Invalid DMP format", b: withUint16(data, 119, 0x0001), err: ErrInvalidDMPFormat, field: "PropertyAddress"},
+		{name: "Truncated sync packet", b: withUint16(sync[:46], 16, 0x701E), err: ErrInvalidLength, field: "Size"},
+		{name: "Sync frame length too long", b: withUint16(sync, 38, 0x7FFF), err: ErrInvalidLength, field: "FrameLength"},
+		{name: "Unhandled frame vector", b: withUint16(sync, 42, 0x0003), err: ErrUnhandledPacketType, field: "FrameVector"},
+		{name: "Truncated discovery packet", b: withUint16(discovery[:115], 16, 0x7063), err: ErrInvalidLength, field: "Size"},
+		{name: "Truncated universe list", b: discovery[:len(discovery)-1], err: ErrInvalidLength, field: "RootLength"},
+		{name: "Discovery layer length too long", b: withUint16(discovery, 112, 0x7FFF), err: ErrInvalidLength, field: "UDLLength"},
+		{name: "Discovery layer length odd", b: withUint16(discovery, 112, 0x700B), err: ErrInvalidLength, field: "UDLLength"},
+		{name: "Discovery layer length too short", b: withUint16(discovery, 112, 0x7002), err: ErrInvalidLength, field: "UDLLength"},
+		{name: "Discovery page after last", b: withUint16(discovery, 118, 0x0605), err: ErrInvalidPage, field: "Page"},
+	}
+
+	for _, tt := range tests {
+		_, err := Unmarshal(tt.b)
+		if !errors.Is(err, tt.err) {
+			t.Fatalf("unexpected error on \"%s\":\n- want: %v\n-  got: %v", tt.name, tt.err, err)
+		}
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Field != tt.field {
+			t.Fatalf("unexpected field in error on \"%s\":\n- want: %v\n-  got: %v", tt.name, tt.field, err)
+		}
+	}
+
+	// Trailing bytes after the packet are ignored
+	if _, err := Unmarshal(append(bytes.Clone(data), 0xff, 0xff)); err != nil {
+		t.Fatalf("unexpected error on packet with trailing bytes: %v", err)
+	}
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	for _, tt := range data_tests {
+		f.Add(tt.b)
+	}
+	for _, tt := range sync_tests {
+		f.Add(tt.b[:])
+	}
+	for _, tt := range discovery_tests {
+		f.Add(tt.b)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		p, err := Unmarshal(b)
+		if err != nil {
+			return
+		}
+
+		// A decoded packet shall survive a round trip
+		m, err := Marshal(p)
+		if err != nil {
+			t.Fatalf("could not marshal decoded packet: %v", err)
+		}
+		q, err := Unmarshal(m)
+		if err != nil {
+			t.Fatalf("could not unmarshal marshalled packet: %v", err)
+		}
+		n, err := Marshal(q)
+		if err != nil {
+			t.Fatalf("could not marshal packet twice: %v", err)
+		}
+		if !bytes.Equal(m, n) {
+			t.Fatalf("unexpected bytes after round trip:\n- want: [%#v]\n-  got: [%#v]", m, n)
+		}
+	})
+}
@@ -1,10 +1,7 @@
 package packet
 
 import (
-	"bytes"
 	"encoding/binary"
-	"errors"
-	"fmt"
 	"strings"
 
 	"github.com/spf13/cast"
@@ -52,7 +49,7 @@ func NewDataPacket() *DataPacket {
 		// Framing Layer
 		FrameVector: VECTOR_E131_DATA_PACKET,
 		FrameLength: 0x7057,
-		Priority:    100,
+		Priority:    DEFAULT_PRIORITY,
 
 		// Data Layer
 		DMPVector:        VECTOR_DMP_SET_PROPERTY,
@@ -115,7 +112,7 @@ func (d *DataPacket) GetSourceName() string {
 // Sets the source name of the packet. Shall not be more than 64 characters.
 func (d *DataPacket) SetSourceName(name string) error {
 	if len(name) > 64 {
-		return errors.New("Source name has to be < 64 bytes")
+		return ErrSourceNameTooLong
 	}
 	copy(d.SourceName[:], []byte(name))
 	return nil
@@ -123,36 +120,40 @@ func (d *DataPacket) SetSourceName(name string) error {
 
 // Returns true if the Preview_Data (bit 7) is set in the Options of the packet. See Section 6.2.6 of ANSI E1.31—2018
 func (d *DataPacket) IsPreviewData() bool {
-	return cast.ToBool(d.Options >> 7)
+	return cast.ToBool((d.Options >> 7) & 0x01)
 }
 
 // Sets the Preview_Data (bit 7) in the Options of the packet. See Section 6.2.6 of ANSI E1.31—2018
 func (d *DataPacket) SetPreviewData(value bool) {
-	d.Options |= cast.ToUint8(value) << 7
+	d.Options = d.Options&^(1<<7) | cast.ToUint8(value)<<7
 }
 
 // Returns true if the Stream_Terminated (bit 6) is set in the Options of the packet. See Section 6.2.6 of ANSI E1.31—2018
 func (d *DataPacket) IsStreamTerminated() bool {
-	return cast.ToBool(d.Options >> 6)
+	return cast.ToBool((d.Options >> 6) & 0x01)
 }
 
 // Sets the Stream_Terminated (bit 6) in the Options of the packet. See Section 6.2.6 of ANSI E1.31—2018
 func (d *DataPacket) SetStreamTerminated(value bool) {
-	d.Options |= cast.ToUint8(value) << 6
+	d.Options = d.Options&^(1<<6) | cast.ToUint8(value)<<6
 }
 
 // Returns true if the Force_Synchronisation (bit 5) is set in the Options of the packet. See Section 6.2.6 of ANSI E1.31—2018
 func (d *DataPacket) IsForceSynchronisation() bool {
-	return cast.ToBool(d.Options >> 5)
+	return cast.ToBool((d.Options >> 5) & 0x01)
 }
 
 // Sets the Force_Synchronisation (bit 5) in the Options of the packet. See Section 6.2.6 of ANSI E1.31—2018
 func (d *DataPacket) SetForceSynchronisation(value bool) {
-	d.Options |= cast.ToUint8(value) << 5
+	d.Options = d.Options&^(1<<5) | cast.ToUint8(value)<<5
 }
 
 // Implements [encoding.BinaryUnmarshaler] for the [DataPacket].
 func (d *DataPacket) UnmarshalBinary(b []byte) error {
+	if err := checkPacketSize(b, dataHeaderSize); err != nil {
+		return err
+	}
+
 	// Root layer
 	err := d.RootLayer.unmarshal(b)
 	if err != nil {
@@ -161,8 +162,8 @@ func (d *DataPacket) UnmarshalBinary(b []byte) error {
 
 	// Framing layer
 	d.FrameLength = binary.BigEndian.Uint16(b[38:40])
-	if d.FrameLength&0x0FFF > uint16(len(b)-38) {
-		return errors.New(fmt.Sprintf("Incorrect packet size %d != %d", d.FrameLength&0x0FFF, len(b)-38))
+	if err := checkPDULength(b, "FrameLength", 38, d.FrameLength, dataHeaderSize-38); err != nil {
+		return err
 	}
 	d.FrameVector = binary.BigEndian.Uint32(b[40:44])
 	copy(d.SourceName[:], b[44:108])
@@ -174,50 +175,101 @@ func (d *DataPacket) UnmarshalBinary(b []byte) error {
 
 	// DMP Layer
 	d.DMPLength = binary.BigEndian.Uint16(b[115:117])
-	if d.DMPLength&0x0FFF > uint16(len(b)-115) {
-		return errors.New(fmt.Sprintf("Incorrect packet size %d != %d", d.DMPLength&0x0FFF, len(b)-115))
+	if err := checkPDULength(b, "DMPLength", 115, d.DMPLength, dataHeaderSize-115); err != nil {
+		return err
 	}
 	d.DMPVector = b[117]
 	d.Format = b[118]
 	d.PropertyAddress = binary.BigEndian.Uint16(b[119:121])
 	d.AddressIncrement = binary.BigEndian.Uint16(b[121:123])
 	d.Length = binary.BigEndian.Uint16(b[123:125])
-	if d.Length&0x0FFF > uint16(len(b)-125) {
-		return errors.New(fmt.Sprintf("Incorrect packet size %d != %d", d.Length&0x0FFF, len(b)-126))
+	length := int(d.Length)
+	if length > len(d.Data) || dataHeaderSize+length > 115+int(d.DMPLength&0x0FFF) {
+		return &FieldError{Field: "Length", Offset: 123, Value: length, Err: ErrInvalidLength}
 	}
-	copy(d.Data[:], b[125:])
+	copy(d.Data[:], b[dataHeaderSize:dataHeaderSize+length])
+	clear(d.Data[length:])
 
 	return d.validate()
 }
 
+// Size in bytes of a DataPacket without its property values
+const dataHeaderSize = 125
+
+// Returns the number of property values (Start Code and DMX512-A data) sent in the packet
+func (d *DataPacket) numValues() int {
+	return min(int(d.Length), len(d.Data))
+}
+
+// Size returns the size in bytes of the marshalled packet.
+func (d *DataPacket) Size() int {
+	return dataHeaderSize + d.numValues()
+}
+
 // Implements [encoding.BinaryMarshaler] for the [DataPacket].
 func (d *DataPacket) MarshalBinary() ([]byte, error) {
-	var buf bytes.Buffer
-	if err := binary.Write(&buf, binary.BigEndian, d); err != nil {
-		return nil, err
+	b := make([]byte, d.Size())
+	_, err := d.MarshalTo(b)
+	return b, err
+}
+
+// MarshalTo marshals the packet into b and returns the number of bytes written.
+// All length fields are derived from the Length of the packet's data, b shall be at least [DataPacket.Size] long.
+func (d *DataPacket) MarshalTo(b []byte) (int, error) {
+	size := d.Size()
+	if err := checkBufferSize(len(b), size); err != nil {
+		return 0, err
 	}
-	buf.Truncate(int(125 + d.Length)) // Truncate unused part of Data array
-	return buf.Bytes(), nil
+
+	// Root layer
+	d.RootLayer.marshal(b, size)
+
+	// Framing layer
+	binary.BigEndian.PutUint16(b[38:40], pduLength(size-38))
+	binary.BigEndian.PutUint32(b[40:44], d.FrameVector)
+	copy(b[44:108], d.SourceName[:])
+	b[108] = d.Priority
+	binary.BigEndian.PutUint16(b[109:111], d.SyncAddress)
+	b[111] = d.Sequence
+	b[112] = d.Options
+	binary.BigEndian.PutUint16(b[113:115], d.Universe)
+
+	// DMP Layer
+	binary.BigEndian.PutUint16(b[115:117], pduLength(size-115))
+	b[117] = d.DMPVector
+	b[118] = d.Format
+	binary.BigEndian.PutUint16(b[119:121], d.PropertyAddress)
+	binary.BigEndian.PutUint16(b[121:123], d.AddressIncrement)
+	binary.BigEndian.PutUint16(b[123:125], uint16(d.numValues()))
+	copy(b[125:size], d.Data[:])
+
+	return size, nil
 }
 
 func (d *DataPacket) validate() error {
 	// Root layer (specifics to DataPacket)
 	if d.RootVector != VECTOR_ROOT_E131_DATA {
-		return errors.New("Invalid Root Vector")
+		return &FieldError{Field: "RootVector", Offset: 18, Value: int(d.RootVector), Err: ErrInvalidRootVector}
 	}
 
 	// Framing layer
 	if d.FrameVector != VECTOR_E131_DATA_PACKET {
-		return errors.New("Invalid Frame Vector")
+		return &FieldError{Field: "FrameVector", Offset: 40, Value: int(d.FrameVector), Err: ErrInvalidFrameVector}
 	}
 
 	// DMP layer
 	if d.DMPVector != VECTOR_DMP_SET_PROPERTY {
-		return errors.New("Invalid DMP Vector")
+		return &FieldError{Field: "DMPVector", Offset: 117, Value: int(d.DMPVector), Err: ErrInvalidDMPVector}
 	}
 	// Statics as defined in Section 7.
-	if d.Format != 0xA1 || d.PropertyAddress != 0 || d.AddressIncrement != 1 {
-		return errors.New("Invalid DMP Formats")
+	if d.Format != 0xA1 {
+		return &FieldError{Field: "Format", Offset: 118, Value: int(d.Format), Err: ErrInvalidDMPFormat}
+	}
+	if d.PropertyAddress != 0 {
+		return &FieldError{Field: "PropertyAddress", Offset: 119, Value: int(d.PropertyAddress), Err: ErrInvalidDMPFormat}
+	}
+	if d.AddressIncrement != 1 {
+		return &FieldError{Field: "AddressIncrement", Offset: 121, Value: int(d.AddressIncrement), Err: ErrInvalidDMPFormat}
 	}
 	return nil
 }
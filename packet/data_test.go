@@ -175,6 +175,37 @@ func TestDataPacketMarshal(t *testing.T) {
 	}
 }
 
+func TestDataPacketMarshalTo(t *testing.T) {
+	buf := make([]byte, MAX_PACKET_SIZE)
+	for _, tt := range data_tests {
+		p := tt.p
+		p.RootLength, p.FrameLength, p.DMPLength = 0, 0, 0 // length fields are derived from the data at marshal time
+
+		n, err := p.MarshalTo(buf)
+		if err != nil {
+			t.Fatalf("unexpected error on \"%s\": %v", tt.name, err)
+		}
+		if !bytes.Equal(tt.b[:], buf[:n]) {
+			t.Fatalf("unexpected bytes on \"%s\":\n- want: [%#v] len:%d\n-  got: [%#v] len:%d", tt.name, tt.b, len(tt.b), buf[:n], n)
+		}
+
+		if _, err := p.MarshalTo(buf[:n-1]); err == nil {
+			t.Fatalf("No error returned with buffer too small on \"%s\"", tt.name)
+		}
+	}
+}
+
+func BenchmarkDataPacketMarshalTo(b *testing.B) {
+	p := NewDataPacket()
+	p.SetData(make([]byte, 512))
+	buf := make([]byte, p.Size())
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p.MarshalTo(buf)
+	}
+}
+
 func TestDataPacketLength(t *testing.T) {
 	for _, tt := range data_tests {
 		length := tt.p.Length
@@ -225,6 +256,14 @@ func TestDataPacketOptions(t *testing.T) {
 	if want, got := true, p.IsPreviewData(); want != got {
 		t.Fatalf("unexpected error on Preview_Data bit:\n- want: %v\n-  got: %v", want, got)
 	}
+
+	p.SetStreamTerminated(false)
+	if want, got := uint8(0b1010_0000), p.Options; want != got {
+		t.Fatalf("unexpected error on clearing Stream_Terminated bit:\n- want: 0x%x\n-  got: 0x%x", want, got)
+	}
+	if want, got := false, p.IsStreamTerminated(); want != got {
+		t.Fatalf("unexpected error on Stream_Terminated bit with Preview_Data set:\n- want: %v\n-  got: %v", want, got)
+	}
 }
 
 func TestDataPacketSourceName(t *testing.T) {
@@ -1,10 +1,7 @@
 package packet
 
 import (
-	"bytes"
 	"encoding/binary"
-	"errors"
-	"fmt"
 	"strings"
 )
 
@@ -26,6 +23,7 @@ type DiscoveryPacket struct {
 	Page      uint8       // The current page number. Multiple pages will be sent if the source sends more than 512 universes.
 	Last      uint8       // The number of pages the source sends.
 	Universes [512]uint16 // The sorted list of universes currently sent by the source.
+	num       int         // number of universes in Universes, see SetUniverses
 }
 
 // Returns a new [DiscoveryPacket] with sensible defaults and empty Universes list.
@@ -57,14 +55,14 @@ func (d *DiscoveryPacket) GetType() SACNPacketType {
 
 // Returns the number of universes in the packet's Universes list.
 func (d *DiscoveryPacket) GetNumUniverses() int {
-	return int(d.UDLLength&0x0FFF-8) / 2
+	return d.num
 }
 
 // Adds a universe at the end of the packet's Universes list,
 func (d *DiscoveryPacket) AddUniverse(universe uint16) error {
 	num := d.GetNumUniverses()
 	if num >= 512 {
-		return errors.New("Universe list is full, please create a new DiscoveryPacket with the next page")
+		return ErrTooManyUniverses
 	}
 	d.Universes[num] = universe
 
@@ -76,7 +74,7 @@ func (d *DiscoveryPacket) AddUniverse(universe uint16) error {
 func (d *DiscoveryPacket) SetUniverses(universes []uint16) error {
 	num := len(universes)
 	if num > 512 {
-		return errors.New("Universe list is too long, please create a new DiscoveryPacket with the next page universes[512:]")
+		return ErrTooManyUniverses
 	}
 	copy(d.Universes[:], universes[:])
 
@@ -85,6 +83,7 @@ func (d *DiscoveryPacket) SetUniverses(universes []uint16) error {
 }
 
 func (d *DiscoveryPacket) setNumUniverses(num uint16) {
+	d.num = int(num)
 	d.UDLLength = 0x7000 | (num*2 + 8)
 	d.FrameLength = d.UDLLength + 74
 	d.RootLength = d.FrameLength + 38
@@ -99,7 +98,7 @@ func (d *DiscoveryPacket) GetSourceName() string {
 // Sets the source name of the packet. Shall not be more than 64 characters.
 func (d *DiscoveryPacket) SetSourceName(name string) error {
 	if len(name) > 64 {
-		return errors.New("Source name has to be < 64 bytes")
+		return ErrSourceNameTooLong
 	}
 	copy(d.SourceName[:], []byte(name))
 	return nil
@@ -107,6 +106,10 @@ func (d *DiscoveryPacket) SetSourceName(name string) error {
 
 // Implements [encoding.BinaryUnmarshaler] for the [DiscoveryPacket].
 func (d *DiscoveryPacket) UnmarshalBinary(b []byte) error {
+	if err := checkPacketSize(b, discoveryHeaderSize); err != nil {
+		return err
+	}
+
 	// Root layer
 	err := d.RootLayer.unmarshal(b)
 	if err != nil {
@@ -115,51 +118,96 @@ func (d *DiscoveryPacket) UnmarshalBinary(b []byte) error {
 
 	// Framing layer
 	d.FrameLength = binary.BigEndian.Uint16(b[38:40])
-	if d.FrameLength&0x0FFF > uint16(len(b)) {
-		return errors.New(fmt.Sprintf("Incorrect packet size %d != %d", d.FrameLength&0x0FFF, len(b)))
+	if err := checkPDULength(b, "FrameLength", 38, d.FrameLength, discoveryHeaderSize-38); err != nil {
+		return err
 	}
 	d.FrameVector = binary.BigEndian.Uint32(b[40:44])
 	copy(d.SourceName[:], b[44:108])
 
 	// Universe Discovery Layer
 	d.UDLLength = binary.BigEndian.Uint16(b[112:114])
+	if err := checkPDULength(b, "UDLLength", 112, d.UDLLength, discoveryHeaderSize-112); err != nil {
+		return err
+	}
+	length := int(d.UDLLength&0x0FFF) - (discoveryHeaderSize - 112)
+	if length%2 != 0 || length/2 > len(d.Universes) {
+		return &FieldError{Field: "UDLLength", Offset: 112, Value: int(d.UDLLength & 0x0FFF), Err: ErrInvalidLength}
+	}
 	d.UDLVector = binary.BigEndian.Uint32(b[114:118])
 	d.Page = b[118]
 	d.Last = b[119]
-	for i, j := 0, 120; j < len(b); i, j = i+1, j+2 {
+	num := length / 2
+	for i, j := 0, discoveryHeaderSize; i < num; i, j = i+1, j+2 {
 		d.Universes[i] = binary.BigEndian.Uint16(b[j : j+2])
 	}
+	clear(d.Universes[num:])
+	d.num = num
 
 	return d.validate()
 }
 
+// Size in bytes of a DiscoveryPacket without its Universes list
+const discoveryHeaderSize = 120
+
+// Size returns the size in bytes of the marshalled packet.
+func (d *DiscoveryPacket) Size() int {
+	return discoveryHeaderSize + d.GetNumUniverses()*2
+}
+
 // Implements [encoding.BinaryMarshaler] for the [DiscoveryPacket].
 func (d *DiscoveryPacket) MarshalBinary() ([]byte, error) {
-	var buf bytes.Buffer
-	if err := binary.Write(&buf, binary.BigEndian, d); err != nil {
-		return nil, err
+	b := make([]byte, d.Size())
+	_, err := d.MarshalTo(b)
+	return b, err
+}
+
+// MarshalTo marshals the packet into b and returns the number of bytes written.
+// All length fields are derived from the number of universes set with [DiscoveryPacket.SetUniverses] or [DiscoveryPacket.AddUniverse],
+// b shall be at least [DiscoveryPacket.Size] long.
+func (d *DiscoveryPacket) MarshalTo(b []byte) (int, error) {
+	size := d.Size()
+	if err := checkBufferSize(len(b), size); err != nil {
+		return 0, err
+	}
+
+	// Root layer
+	d.RootLayer.marshal(b, size)
+
+	// Framing layer
+	binary.BigEndian.PutUint16(b[38:40], pduLength(size-38))
+	binary.BigEndian.PutUint32(b[40:44], d.FrameVector)
+	copy(b[44:108], d.SourceName[:])
+	copy(b[108:112], d.reserved[:])
+
+	// Universe Discovery Layer
+	binary.BigEndian.PutUint16(b[112:114], pduLength(size-112))
+	binary.BigEndian.PutUint32(b[114:118], d.UDLVector)
+	b[118] = d.Page
+	b[119] = d.Last
+	for i, j := 0, discoveryHeaderSize; j < size; i, j = i+1, j+2 {
+		binary.BigEndian.PutUint16(b[j:j+2], d.Universes[i])
 	}
-	buf.Truncate(int(120 + d.GetNumUniverses()*2)) // Truncate unused part of Universes array
-	return buf.Bytes(), nil
+
+	return size, nil
 }
 
 func (d *DiscoveryPacket) validate() error {
 	// Root layer (specifics to DataPacket)
 	if d.RootVector != VECTOR_ROOT_E131_EXTENDED {
-		return errors.New("Invalid Root Vector")
+		return &FieldError{Field: "RootVector", Offset: 18, Value: int(d.RootVector), Err: ErrInvalidRootVector}
 	}
 
 	// Framing layer
 	if d.FrameVector != VECTOR_E131_EXTENDED_DISCOVERY {
-		return errors.New("Invalid Frame Vector")
+		return &FieldError{Field: "FrameVector", Offset: 40, Value: int(d.FrameVector), Err: ErrInvalidFrameVector}
 	}
 
 	// Universe Discovery Layerr
 	if d.UDLVector != VECTOR_UNIVERSE_DISCOVERY_UNIVERSE_LIST {
-		return errors.New("Invalid Discovery Vector")
+		return &FieldError{Field: "UDLVector", Offset: 114, Value: int(d.UDLVector), Err: ErrInvalidDiscoveryVector}
 	}
 	if d.Page > d.Last {
-		return errors.New("Current page > Last page")
+		return &FieldError{Field: "Page", Offset: 118, Value: int(d.Page), Err: ErrInvalidPage}
 	}
 
 	return nil
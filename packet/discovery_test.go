@@ -32,6 +32,7 @@ var discovery_tests = []struct {
 			Page:      3,
 			Last:      5,
 			Universes: [512]uint16{0x01, 0x64},
+			num:       2,
 		},
 		b: []byte{
 			0x00, 0x10, 0x00, 0x00, 0x41, 0x53, 0x43, 0x2d, 0x45, 0x31, 0x2e, 0x31, 0x37, 0x00, 0x00, 0x00, 0x70, 0x6c,
@@ -133,6 +134,32 @@ func TestDiscoveryPacketMarshal(t *testing.T) {
 	}
 }
 
+func TestDiscoveryPacketMarshalTo(t *testing.T) {
+	buf := make([]byte, MAX_PACKET_SIZE)
+	for _, tt := range discovery_tests {
+		p := tt.p
+		p.RootLength, p.FrameLength, p.UDLLength = 0, 0, 0 // length fields are derived from the number of universes at marshal time
+
+		n, err := p.MarshalTo(buf)
+		if err != nil {
+			t.Fatalf("unexpected error on \"%s\": %v", tt.name, err)
+		}
+		if !bytes.Equal(tt.b[:], buf[:n]) {
+			t.Fatalf("unexpected bytes on \"%s\":\n- want: [%#v] len:%d\n-  got: [%#v] len:%d", tt.name, tt.b, len(tt.b), buf[:n], n)
+		}
+
+		if _, err := p.MarshalTo(buf[:n-1]); err == nil {
+			t.Fatalf("No error returned with buffer too small on \"%s\"", tt.name)
+		}
+	}
+
+	p := NewDiscoveryPacket()
+	p.SetUniverses(make([]uint16, 512))
+	if want, got := MAX_PACKET_SIZE, p.Size(); want != got {
+		t.Fatalf("unexpected size of full discovery packet:\n- want: %d\n-  got: %d", want, got)
+	}
+}
+
 func TestDiscoveryPacketUniverses(t *testing.T) {
 	p := NewDiscoveryPacket()
 
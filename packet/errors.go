@@ -0,0 +1,41 @@
+package packet
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned when marshalling, unmarshalling or validating a [SACNPacket].
+// Errors of malformed packets are wrapped in a [FieldError], use [errors.Is] to check their kind.
+var (
+	ErrInvalidLength          = errors.New("Incorrect packet length")
+	ErrInvalidRootLayer       = errors.New("Invalid Root Layer")
+	ErrInvalidRootVector      = errors.New("Invalid Root Vector")
+	ErrInvalidFrameVector     = errors.New("Invalid Frame Vector")
+	ErrInvalidDMPVector       = errors.New("Invalid DMP Vector")
+	ErrInvalidDMPFormat       = errors.New("Invalid DMP Formats")
+	ErrInvalidDiscoveryVector = errors.New("Invalid Discovery Vector")
+	ErrInvalidPage            = errors.New("Current page > Last page")
+	ErrUnhandledPacketType    = errors.New("Unhandled packet type")
+
+	ErrBufferTooSmall    = errors.New("Buffer too small to marshal packet")
+	ErrSourceNameTooLong = errors.New("Source name is too long. Maximum is 64 bytes")
+	ErrTooManyUniverses  = errors.New("Universe list is limited to 512 universes, please create a new DiscoveryPacket with the next page")
+)
+
+// FieldError describes the field of a packet which failed to unmarshal or validate.
+// Use [errors.As] to retrieve it and [errors.Is] to check the kind of error it wraps.
+type FieldError struct {
+	Field  string // Name of the field in the packet struct, "Size" for the size of the whole packet.
+	Offset int    // Offset of the field in the marshalled packet.
+	Value  int    // Value of the field, the size of the packet for "Size" and 0 for byte arrays.
+	Err    error  // The kind of error, one of the errors of this package.
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%v: %s is 0x%x at offset %d", e.Err, e.Field, e.Value, e.Offset)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
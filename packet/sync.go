@@ -1,10 +1,7 @@
 package packet
 
 import (
-	"bytes"
 	"encoding/binary"
-	"errors"
-	"fmt"
 )
 
 // SyncPacket is used to synchronize multiple universes. See Section 11 of ANSI E1.31—2018.
@@ -48,6 +45,10 @@ func (d *SyncPacket) GetType() SACNPacketType {
 
 // Implements [encoding.BinaryUnmarshaler] for the [SyncPacket].
 func (d *SyncPacket) UnmarshalBinary(b []byte) error {
+	if err := checkPacketSize(b, syncPacketSize); err != nil {
+		return err
+	}
+
 	// Root layer
 	err := d.RootLayer.unmarshal(b)
 	if err != nil {
@@ -56,8 +57,8 @@ func (d *SyncPacket) UnmarshalBinary(b []byte) error {
 
 	// Framing layer
 	d.FrameLength = binary.BigEndian.Uint16(b[38:40])
-	if d.FrameLength&0x0FFF > uint16(len(b)) {
-		return errors.New(fmt.Sprintf("Incorrect packet size %d != %d", d.FrameLength&0x0FFF, len(b)))
+	if err := checkPDULength(b, "FrameLength", 38, d.FrameLength, syncPacketSize-38); err != nil {
+		return err
 	}
 	d.FrameVector = binary.BigEndian.Uint32(b[40:44])
 	d.Sequence = b[44]
@@ -66,24 +67,51 @@ func (d *SyncPacket) UnmarshalBinary(b []byte) error {
 	return d.validate()
 }
 
+// Size in bytes of a SyncPacket
+const syncPacketSize = 49
+
+// Size returns the size in bytes of the marshalled packet.
+func (d *SyncPacket) Size() int {
+	return syncPacketSize
+}
+
 // Implements [encoding.BinaryMarshaler] for the [SyncPacket].
 func (d *SyncPacket) MarshalBinary() ([]byte, error) {
-	var buf bytes.Buffer
-	if err := binary.Write(&buf, binary.BigEndian, d); err != nil {
-		return nil, err
+	b := make([]byte, d.Size())
+	_, err := d.MarshalTo(b)
+	return b, err
+}
+
+// MarshalTo marshals the packet into b and returns the number of bytes written.
+// b shall be at least [SyncPacket.Size] long.
+func (d *SyncPacket) MarshalTo(b []byte) (int, error) {
+	size := d.Size()
+	if err := checkBufferSize(len(b), size); err != nil {
+		return 0, err
 	}
-	return buf.Bytes(), nil
+
+	// Root layer
+	d.RootLayer.marshal(b, size)
+
+	// Framing layer
+	binary.BigEndian.PutUint16(b[38:40], pduLength(size-38))
+	binary.BigEndian.PutUint32(b[40:44], d.FrameVector)
+	b[44] = d.Sequence
+	binary.BigEndian.PutUint16(b[45:47], d.SyncAddress)
+	copy(b[47:49], d.reserved[:])
+
+	return size, nil
 }
 
 func (d *SyncPacket) validate() error {
 	// Root layer (specifics to SyncPacket)
 	if d.RootVector != VECTOR_ROOT_E131_EXTENDED {
-		return errors.New("Invalid Root Vector")
+		return &FieldError{Field: "RootVector", Offset: 18, Value: int(d.RootVector), Err: ErrInvalidRootVector}
 	}
 
 	// Framing layer
 	if d.FrameVector != VECTOR_E131_EXTENDED_SYNCHRONIZATION {
-		return errors.New("Invalid Frame Vector")
+		return &FieldError{Field: "FrameVector", Offset: 40, Value: int(d.FrameVector), Err: ErrInvalidFrameVector}
 	}
 
 	return nil
@@ -92,6 +92,26 @@ func TestSyncPacketMarshal(t *testing.T) {
 	}
 }
 
+func TestSyncPacketMarshalTo(t *testing.T) {
+	buf := make([]byte, MAX_PACKET_SIZE)
+	for _, tt := range sync_tests {
+		p := tt.p
+		p.RootLength, p.FrameLength = 0, 0 // length fields are derived at marshal time
+
+		n, err := p.MarshalTo(buf)
+		if err != nil {
+			t.Fatalf("unexpected error on \"%s\": %v", tt.name, err)
+		}
+		if !bytes.Equal(tt.b[:], buf[:n]) {
+			t.Fatalf("unexpected bytes on \"%s\":\n- want: [%#v] len:%d\n-  got: [%#v] len:%d", tt.name, tt.b, len(tt.b), buf[:n], n)
+		}
+
+		if _, err := p.MarshalTo(buf[:n-1]); err == nil {
+			t.Fatalf("No error returned with buffer too small on \"%s\"", tt.name)
+		}
+	}
+}
+
 // func TestSyncPacketData(t *testing.T) {
 // 	for _, tt := range sync_tests {
 // 		s := NewSyncPacket()
@@ -0,0 +1,317 @@
+// Package playback plays cue lists of looks across many universes on top of a [sacn.Sender].
+package playback
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"gitlab.com/patopest/go-sacn"
+)
+
+// Output is where a [Player] sends its levels. It is implemented by [sacn.Sender].
+type Output interface {
+	GetSlots(universe uint16) ([]byte, error)
+	SetSlots(universe uint16, start int, values []byte) error
+}
+
+// Clock provides the time to a [Player]. It can be replaced in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// A Look is the state of several universes. It maps universe numbers to their slot values, starting at slot 1.
+// Slots and universes which are not part of a look keep their current values when it is played (tracking).
+type Look map[uint16][]byte
+
+// A Cue is a step of a cue list.
+type Cue struct {
+	Name   string
+	Look   Look
+	Delay  time.Duration  // Time between the cue being triggered and the start of its fade.
+	FadeIn time.Duration  // Duration of the fade to the cue's look.
+	Curve  sacn.FadeCurve // Curve of the fade.
+	Follow time.Duration  // If not 0, the next cue is triggered automatically this long after this cue was triggered.
+}
+
+// ErrorCallbackFunc is called with the errors returned by the [Output] of a [Player], eg: for a universe which was not started.
+type ErrorCallbackFunc func(universe uint16, err error)
+
+// Optional arguments for [NewPlayer].
+type PlayerOptions struct {
+	Clock   Clock             // Defaults to the system clock.
+	Rate    float64           // Number of times per second levels are computed and sent to the output. Defaults to [sacn.DMX_FRAME_RATE].
+	OnError ErrorCallbackFunc // Optionally called when reading or setting the levels of the output fails.
+}
+
+// A Player plays a cue list on an [Output]. Use [NewPlayer] to create a player.
+type Player struct {
+	mu       sync.Mutex
+	output   Output
+	clock    Clock
+	interval time.Duration
+	cues     []Cue
+	stop     chan bool // nil while stopped
+	onError  ErrorCallbackFunc
+	failures []outputError // errors of the output to report once mu is released
+
+	current   int               // index of the last triggered cue, -1 before the first GO
+	triggered time.Time         // when the current cue was triggered, shifted by the time spent paused
+	delay     time.Duration     // delay of the current cue
+	follow    time.Duration     // follow of the current cue, 0 if disabled
+	from      map[uint16][]byte // levels when the current cue was triggered
+	levels    map[uint16][]byte // levels currently sent to the output
+	paused    bool
+	pausedAt  time.Time
+}
+
+type outputError struct {
+	universe uint16
+	err      error
+}
+
+// Errors returned when navigating the cue list of a [Player].
+var (
+	ErrEndOfCueList       = errors.New("No more cues in the cue list")
+	ErrCueIndexOutOfRange = errors.New("Cue index is out of range")
+)
+
+// NewPlayer creates a new [Player] for a cue list. options can be nil to use the defaults.
+// Use [Player.Start] to start sending levels to the output.
+func NewPlayer(output Output, cues []Cue, options *PlayerOptions) *Player {
+	p := &Player{
+		output:  output,
+		clock:   realClock{},
+		cues:    cues,
+		current: -1,
+		from:    make(map[uint16][]byte),
+		levels:  make(map[uint16][]byte),
+	}
+	rate := float64(sacn.DMX_FRAME_RATE)
+	if options != nil {
+		if options.Clock != nil {
+			p.clock = options.Clock
+		}
+		if options.Rate > 0 {
+			rate = options.Rate
+		}
+		p.onError = options.OnError
+	}
+	p.interval = time.Duration(float64(time.Second) / rate)
+	return p
+}
+
+// Start starts computing levels and sending them to the output.
+func (p *Player) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stop == nil {
+		p.stop = make(chan bool)
+		go p.loop(p.stop)
+	}
+}
+
+// Stop stops sending levels to the output. The output keeps its last levels.
+func (p *Player) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+}
+
+// Go triggers the next cue.
+func (p *Player) Go() error {
+	defer p.reportErrors()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.current+1 >= len(p.cues) {
+		return ErrEndOfCueList
+	}
+	p.trigger(p.current+1, p.clock.Now())
+	return nil
+}
+
+// Back returns to the previous cue. It fades to its look with its fade time, without delay nor follow.
+func (p *Player) Back() error {
+	defer p.reportErrors()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.current <= 0 {
+		return ErrEndOfCueList
+	}
+	p.trigger(p.current-1, p.clock.Now())
+	p.delay = 0
+	p.follow = 0
+	return nil
+}
+
+// Jump triggers the cue at index (starting at 0) with all its timings.
+func (p *Player) Jump(index int) error {
+	defer p.reportErrors()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if index < 0 || index >= len(p.cues) {
+		return ErrCueIndexOutOfRange
+	}
+	p.trigger(index, p.clock.Now())
+	return nil
+}
+
+// Pause freezes the running fade, delay and follow of the current cue.
+func (p *Player) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.paused {
+		p.paused = true
+		p.pausedAt = p.clock.Now()
+	}
+}
+
+// Resume continues the cue paused by [Player.Pause].
+func (p *Player) Resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.paused {
+		p.paused = false
+		p.triggered = p.triggered.Add(p.clock.Now().Sub(p.pausedAt))
+	}
+}
+
+// IsPaused returns true if the player is paused.
+func (p *Player) IsPaused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.paused
+}
+
+// Current returns the index of the current cue, or -1 if no cue was triggered yet.
+func (p *Player) Current() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.current
+}
+
+func (p *Player) loop(stop <-chan bool) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-p.clock.After(p.interval):
+			p.update()
+		}
+	}
+}
+
+// The caller shall hold mu.
+func (p *Player) trigger(index int, now time.Time) {
+	p.current = index
+	p.triggered = now
+	p.delay = p.cues[index].Delay
+	p.follow = p.cues[index].Follow
+	p.paused = false // triggering a cue resumes the player
+
+	// Fade from the levels currently sent, or the levels of the output for universes not played yet
+	for universe := range p.cues[index].Look {
+		if _, exists := p.levels[universe]; !exists {
+			levels := make([]byte, 512)
+			if current, err := p.output.GetSlots(universe); err == nil {
+				copy(levels, current)
+			} else {
+				p.failures = append(p.failures, outputError{universe: universe, err: err})
+			}
+			p.levels[universe] = levels
+		}
+	}
+	p.from = make(map[uint16][]byte, len(p.levels))
+	for universe, levels := range p.levels {
+		p.from[universe] = append([]byte(nil), levels...)
+	}
+}
+
+// update computes the levels of the current cue and sends the changed universes to the output.
+func (p *Player) update() {
+	defer p.reportErrors()
+	p.mu.Lock()
+
+	if p.current < 0 {
+		p.mu.Unlock()
+		return
+	}
+	now := p.clock.Now()
+	if p.paused {
+		now = p.pausedAt
+	}
+
+	// Trigger followed cues at the exact time they were due
+	for p.follow > 0 && now.Sub(p.triggered) >= p.follow && p.current+1 < len(p.cues) {
+		p.trigger(p.current+1, p.triggered.Add(p.follow))
+	}
+
+	cue := p.cues[p.current]
+	elapsed := now.Sub(p.triggered)
+	started := elapsed >= p.delay
+	progress := 1.0
+	if cue.FadeIn > 0 {
+		progress = float64(elapsed-p.delay) / float64(cue.FadeIn)
+	}
+
+	output := make(map[uint16][]byte)
+	for universe, target := range cue.Look {
+		from := p.from[universe]
+		levels := p.levels[universe]
+		length := min(len(target), 512)
+		changed := false
+		for i := 0; i < length; i++ {
+			value := from[i]
+			if started {
+				value = cue.Curve.Interpolate(from[i], target[i], progress)
+			}
+			if levels[i] != value {
+				levels[i] = value
+				changed = true
+			}
+		}
+		if changed { // only the slots of the look, copied so the output is called without holding the lock
+			output[universe] = append([]byte(nil), levels[:length]...)
+		}
+	}
+	p.mu.Unlock()
+
+	for universe, levels := range output {
+		if err := p.output.SetSlots(universe, 1, levels); err != nil {
+			p.mu.Lock()
+			p.failures = append(p.failures, outputError{universe: universe, err: err})
+			p.mu.Unlock()
+		}
+	}
+}
+
+// reportErrors passes the errors of the output to the error callback, without holding mu so it can use the player.
+func (p *Player) reportErrors() {
+	p.mu.Lock()
+	failures := p.failures
+	p.failures = nil
+	p.mu.Unlock()
+
+	if p.onError == nil {
+		return
+	}
+	for _, failure := range failures {
+		p.onError(failure.universe, failure.err)
+	}
+}
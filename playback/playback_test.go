@@ -0,0 +1,245 @@
+package playback
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/patopest/go-sacn"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time                         { return c.now }
+func (c *fakeClock) After(d time.Duration) <-chan time.Time { return make(chan time.Time) }
+
+type fakeOutput struct {
+	mu     sync.Mutex
+	levels map[uint16][]byte
+}
+
+func (o *fakeOutput) GetSlots(universe uint16) ([]byte, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	levels, exists := o.levels[universe]
+	if !exists {
+		return nil, sacn.ErrUniverseNotFound
+	}
+	return append([]byte(nil), levels...), nil
+}
+
+func (o *fakeOutput) SetSlots(universe uint16, start int, values []byte) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	levels, exists := o.levels[universe]
+	if !exists {
+		levels = make([]byte, 512)
+		o.levels[universe] = levels
+	}
+	copy(levels[start-1:], values)
+	return nil
+}
+
+func TestPlayer(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	output := &fakeOutput{levels: make(map[uint16][]byte)}
+	cues := []Cue{
+		{
+			Name:   "Preset",
+			Look:   Look{1: {100, 200}},
+			FadeIn: 1 * time.Second,
+			Curve:  sacn.FadeLinear,
+		},
+		{
+			Name:   "Delayed",
+			Look:   Look{1: {0}, 2: {255}},
+			Delay:  1 * time.Second,
+			FadeIn: 2 * time.Second,
+			Curve:  sacn.FadeLinear,
+			Follow: 4 * time.Second,
+		},
+		{
+			Name:  "Snap",
+			Look:  Look{2: {10}},
+			Curve: sacn.FadeSnap,
+		},
+	}
+	p := NewPlayer(output, cues, &PlayerOptions{Clock: clock})
+
+	tests := []struct {
+		name     string
+		action   func() error
+		advance  time.Duration
+		current  int
+		expected map[uint16][]byte // first slots of each universe
+	}{
+		{
+			name:     "Nothing triggered",
+			current:  -1,
+			expected: map[uint16][]byte{},
+		},
+		{
+			name:     "GO",
+			action:   p.Go,
+			advance:  500 * time.Millisecond,
+			current:  0,
+			expected: map[uint16][]byte{1: {50, 100}},
+		},
+		{
+			name:     "Fade done",
+			advance:  1 * time.Second,
+			current:  0,
+			expected: map[uint16][]byte{1: {100, 200}},
+		},
+		{
+			name:     "GO with delay",
+			action:   p.Go,
+			advance:  500 * time.Millisecond,
+			current:  1,
+			expected: map[uint16][]byte{1: {100, 200}},
+		},
+		{
+			name:     "Fade after delay",
+			advance:  1500 * time.Millisecond,
+			current:  1,
+			expected: map[uint16][]byte{1: {50, 200}, 2: {128}},
+		},
+		{
+			name:     "Paused",
+			action:   func() error { p.Pause(); return nil },
+			advance:  10 * time.Second,
+			current:  1,
+			expected: map[uint16][]byte{1: {50, 200}, 2: {128}},
+		},
+		{
+			name:     "Resumed",
+			action:   func() error { p.Resume(); return nil },
+			advance:  1 * time.Second,
+			current:  1,
+			expected: map[uint16][]byte{1: {0, 200}, 2: {255}},
+		},
+		{
+			name:     "Follow",
+			advance:  1 * time.Second,
+			current:  2,
+			expected: map[uint16][]byte{1: {0, 200}, 2: {10}},
+		},
+		{
+			name:     "Back",
+			action:   p.Back,
+			advance:  1 * time.Second,
+			current:  1,
+			expected: map[uint16][]byte{1: {0, 200}, 2: {133}},
+		},
+		{
+			name:     "Jump",
+			action:   func() error { return p.Jump(0) },
+			advance:  1 * time.Second,
+			current:  0,
+			expected: map[uint16][]byte{1: {100, 200}, 2: {133}},
+		},
+	}
+
+	for _, tt := range tests {
+		if tt.action != nil {
+			if err := tt.action(); err != nil {
+				t.Fatalf("unexpected error on \"%s\": %v", tt.name, err)
+			}
+		}
+		clock.now = clock.now.Add(tt.advance)
+		p.update()
+
+		if got := p.Current(); got != tt.current {
+			t.Fatalf("unexpected current cue on \"%s\":\n- want: %d\n-  got: %d", tt.name, tt.current, got)
+		}
+		for universe, expected := range tt.expected {
+			levels := output.levels[universe]
+			if levels == nil || !bytes.Equal(expected, levels[:len(expected)]) {
+				t.Fatalf("unexpected levels on universe %d on \"%s\":\n- want: %v\n-  got: %v", universe, tt.name, expected, levels[:len(expected)])
+			}
+		}
+	}
+
+	if err := p.Jump(3); !errors.Is(err, ErrCueIndexOutOfRange) {
+		t.Fatalf("No error returned on jump out of range")
+	}
+	p.Jump(2)
+	if err := p.Go(); !errors.Is(err, ErrEndOfCueList) {
+		t.Fatalf("unexpected error on GO at the end of the cue list: %v", err)
+	}
+}
+
+func TestPlayerOutputLevels(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	output := &fakeOutput{levels: map[uint16][]byte{1: append([]byte{200, 0, 77}, make([]byte, 509)...)}}
+	cues := []Cue{
+		{
+			Name:   "Preset",
+			Look:   Look{1: {100, 200}},
+			FadeIn: 1 * time.Second,
+			Curve:  sacn.FadeLinear,
+		},
+	}
+	p := NewPlayer(output, cues, &PlayerOptions{Clock: clock})
+	p.Stop() // stopping a player which was not started does nothing
+
+	// The fade starts from the levels of the output
+	p.Go()
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	p.update()
+	if expected, levels := []byte{150, 100, 77}, output.levels[1][:3]; !bytes.Equal(expected, levels) {
+		t.Fatalf("unexpected levels during fade from the output's levels:\n- want: %v\n-  got: %v", expected, levels)
+	}
+
+	// Slots which are not part of the look are left to other writers
+	output.SetSlots(1, 3, []byte{99})
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	p.update()
+	if expected, levels := []byte{100, 200, 99}, output.levels[1][:3]; !bytes.Equal(expected, levels) {
+		t.Fatalf("unexpected levels after fade:\n- want: %v\n-  got: %v", expected, levels)
+	}
+
+	p.Start()
+	p.Stop()
+	p.Stop()
+}
+
+// failingOutput has no universe started
+type failingOutput struct{}
+
+func (failingOutput) GetSlots(universe uint16) ([]byte, error) {
+	return nil, &sacn.UniverseError{Universe: universe, Err: sacn.ErrUniverseNotFound}
+}
+
+func (failingOutput) SetSlots(universe uint16, start int, values []byte) error {
+	return &sacn.UniverseError{Universe: universe, Err: sacn.ErrUniverseNotFound}
+}
+
+func TestPlayerOutputErrors(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	var reported []uint16
+	options := &PlayerOptions{
+		Clock: clock,
+		OnError: func(universe uint16, err error) {
+			if !errors.Is(err, sacn.ErrUniverseNotFound) {
+				t.Fatalf("unexpected error reported on universe %d: %v", universe, err)
+			}
+			reported = append(reported, universe)
+		},
+	}
+	p := NewPlayer(failingOutput{}, []Cue{{Name: "Not started", Look: Look{5: {255}}}}, options)
+
+	// Reading the levels to fade from fails when the cue is triggered, sending them fails on update
+	p.Go()
+	if want, got := []uint16{5}, reported; len(got) != 1 || got[0] != want[0] {
+		t.Fatalf("unexpected errors reported on GO:\n- want: %v\n-  got: %v", want, got)
+	}
+	p.update()
+	if want, got := []uint16{5, 5}, reported; len(got) != 2 || got[1] != want[1] {
+		t.Fatalf("unexpected errors reported on update:\n- want: %v\n-  got: %v", want, got)
+	}
+}
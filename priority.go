@@ -0,0 +1,93 @@
+package sacn
+
+import (
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+// GetPerAddressPriority returns the per-address priorities sent on the universe, or nil if none are set.
+func (s *Sender) GetPerAddressPriority(universe uint16) ([]byte, error) {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		defer uni.mu.Unlock()
+		if uni.priorities == nil {
+			return nil, nil
+		}
+		return append([]byte(nil), uni.priorities...), nil
+	}
+	return nil, universeNotFound(universe)
+}
+
+// SetPerAddressPriority sets a priority for each slot of the universe (up to 512), starting at slot 1. Use nil to stop sending them.
+//
+// Priorities are sent as [packet.DataPacket] with the [packet.START_CODE_PER_ADDRESS_PRIORITY] Start Code alongside the level data,
+// every [PER_ADDRESS_PRIORITY_INTERVAL] and immediately on change.
+// Values range from 1 to 200, 0 means the source does not control the slot so receivers can use other sources for it.
+// They stop together with the universe.
+func (s *Sender) SetPerAddressPriority(universe uint16, priorities []byte) error {
+	if len(priorities) > 512 {
+		return ErrSlotOutOfRange
+	}
+	for _, p := range priorities {
+		if p > 200 {
+			return ErrInvalidPriority
+		}
+	}
+
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		if priorities == nil {
+			uni.priorities = nil
+		} else {
+			uni.priorities = append(make([]byte, 0, len(priorities)), priorities...)
+		}
+		uni.prioritiesChanged = true
+		uni.mu.Unlock()
+		uni.wake()
+		return nil
+	}
+	return universeNotFound(universe)
+}
+
+// sendPriorities sends the per-address priorities of a universe, sharing the sequence of its level data.
+// It returns false if the universe has no per-address priorities.
+func (s *Sender) sendPriorities(uni *senderUniverse) bool {
+	uni.mu.Lock()
+	priorities := uni.priorities
+	paused := uni.paused
+	uni.mu.Unlock()
+	if priorities == nil {
+		return false
+	}
+	if paused { // keep the timer running to send them again on resume
+		return true
+	}
+
+	uni.txMu.Lock()
+	defer uni.txMu.Unlock()
+
+	p := packet.NewDataPacket()
+	p.SetStartCode(packet.START_CODE_PER_ADDRESS_PRIORITY)
+	p.SetData(priorities)
+	p.Universe = uni.number
+	if uni.last != nil { // same framing as the level data
+		p.CID = uni.last.CID
+		p.SourceName = uni.last.SourceName
+		p.Priority = uni.last.Priority
+	}
+	f := s.frameData(uni, p)
+	uni.sequence += 1
+	f.Sequence = uni.sequence
+	s.sendPacket(uni, f)
+	return true
+}
+
+// Returns true once after the per-address priorities were changed
+func (uni *senderUniverse) takePrioritiesChanged() bool {
+	uni.mu.Lock()
+	defer uni.mu.Unlock()
+	changed := uni.prioritiesChanged
+	uni.prioritiesChanged = false
+	return changed
+}
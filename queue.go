@@ -0,0 +1,134 @@
+package sacn
+
+import (
+	"sync"
+
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+// BackpressurePolicy defines what a [Sender] does when packets are written to a universe faster than they can be sent out.
+type BackpressurePolicy int
+
+// Possible backpressure policies. See [Sender.SetBackpressure].
+const (
+	BackpressureBlock      BackpressurePolicy = iota // Writers wait for room in the queue (default).
+	BackpressureDropNewest                           // New packets are discarded while the queue is full.
+	BackpressureLatestWins                           // Queued DataPackets are replaced by the newest packet when the queue is full.
+)
+
+// Default number of packets which can be queued per universe.
+const DEFAULT_SEND_QUEUE_SIZE = 3
+
+// Queue of packets waiting to be sent on a universe, applying a BackpressurePolicy when full
+type sendQueue struct {
+	mu      sync.Mutex
+	room    *sync.Cond // signalled when packets are removed from the queue
+	packets []packet.SACNPacket
+	size    int
+	policy  BackpressurePolicy
+	dropped uint64
+	closed  bool
+	ready   chan struct{} // signalled when packets are added or the queue is closed
+}
+
+func newSendQueue(size int, policy BackpressurePolicy) *sendQueue {
+	if size <= 0 {
+		size = DEFAULT_SEND_QUEUE_SIZE
+	}
+	q := &sendQueue{
+		packets: make([]packet.SACNPacket, 0, size),
+		size:    size,
+		policy:  policy,
+		ready:   make(chan struct{}, 1),
+	}
+	q.room = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *sendQueue) push(p packet.SACNPacket) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.packets) >= q.size && !q.closed {
+		switch q.policy {
+		case BackpressureDropNewest:
+			q.dropped += 1
+			return
+		case BackpressureLatestWins:
+			kept := q.packets[:0]
+			for _, queued := range q.packets {
+				if queued.GetType() == packet.PacketTypeData {
+					q.dropped += 1
+				} else {
+					kept = append(kept, queued)
+				}
+			}
+			q.packets = kept
+			if len(q.packets) >= q.size { // only non-data packets were queued
+				q.packets = q.packets[1:]
+				q.dropped += 1
+			}
+		default: // BackpressureBlock
+			q.room.Wait()
+		}
+	}
+	if q.closed {
+		return
+	}
+	q.packets = append(q.packets, p)
+	q.signal()
+}
+
+// pop returns the oldest packet of the queue, or false if it is empty.
+func (q *sendQueue) pop() (packet.SACNPacket, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.packets) == 0 {
+		return nil, false
+	}
+	p := q.packets[0]
+	q.packets[0] = nil
+	q.packets = q.packets[1:]
+	q.room.Broadcast()
+	return p, true
+}
+
+// done returns true once the queue is closed and all its packets were popped.
+func (q *sendQueue) done() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.closed && len(q.packets) == 0
+}
+
+func (q *sendQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.room.Broadcast()
+	q.signal()
+}
+
+func (q *sendQueue) configure(size int, policy BackpressurePolicy) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if size <= 0 {
+		size = DEFAULT_SEND_QUEUE_SIZE
+	}
+	q.size = size
+	q.policy = policy
+	q.room.Broadcast()
+}
+
+func (q *sendQueue) getDropped() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
+
+func (q *sendQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default: // already signalled
+	}
+}
@@ -0,0 +1,87 @@
+package sacn
+
+import (
+	"testing"
+	"time"
+
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+func TestSendQueue(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   BackpressurePolicy
+		expected []byte // first slot of the queued DataPackets
+		dropped  uint64
+	}{
+		{
+			name:     "Drop newest",
+			policy:   BackpressureDropNewest,
+			expected: []byte{0, 1},
+			dropped:  3,
+		},
+		{
+			name:     "Latest wins",
+			policy:   BackpressureLatestWins,
+			expected: []byte{4},
+			dropped:  4,
+		},
+	}
+
+	for _, tt := range tests {
+		q := newSendQueue(2, tt.policy)
+		for i := 0; i < 5; i++ {
+			p := packet.NewDataPacket()
+			p.SetData([]byte{byte(i)})
+			q.push(p)
+		}
+
+		got := make([]byte, 0)
+		for {
+			p, ok := q.pop()
+			if !ok {
+				break
+			}
+			got = append(got, p.(*packet.DataPacket).GetData()[0])
+		}
+		if string(got) != string(tt.expected) {
+			t.Fatalf("unexpected packets on \"%s\":\n- want: %v\n-  got: %v", tt.name, tt.expected, got)
+		}
+		if dropped := q.getDropped(); dropped != tt.dropped {
+			t.Fatalf("unexpected dropped count on \"%s\":\n- want: %v\n-  got: %v", tt.name, tt.dropped, dropped)
+		}
+	}
+}
+
+func TestSendQueueBlock(t *testing.T) {
+	q := newSendQueue(1, BackpressureBlock)
+	q.push(packet.NewDataPacket())
+
+	pushed := make(chan bool)
+	go func() {
+		q.push(packet.NewDataPacket())
+		close(pushed)
+	}()
+
+	select {
+	case <-pushed:
+		t.Fatalf("push did not block on a full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	q.pop()
+	select {
+	case <-pushed:
+	case <-time.After(time.Second):
+		t.Fatalf("push still blocked after a packet was popped")
+	}
+
+	q.close()
+	if q.done() {
+		t.Fatalf("queue done with a packet left")
+	}
+	q.pop()
+	if !q.done() {
+		t.Fatalf("queue not done after being closed and emptied")
+	}
+}
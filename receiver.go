@@ -1,11 +1,13 @@
 package sacn
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"golang.org/x/net/ipv4"
-	"log"
+	"log/slog"
 	"net"
+	"sync"
 	"time"
 
 	"github.com/libp2p/go-reuseport"
@@ -27,8 +29,22 @@ const (
 
 // Struct of additional packet information when calling [PacketCallbackFunc] callbacks.
 type PacketInfo struct {
-	Source net.UDPAddr // The source address of the packet.
-	Mode   PacketMode  // How the packet was received. (WARNING: Not available on Windows due to https://github.com/golang/go/issues/7175)
+	Source          net.UDPAddr // The source address of the packet.
+	Mode            PacketMode  // How the packet was received. (WARNING: Not available on Windows due to https://github.com/golang/go/issues/7175)
+	Timestamp       time.Time   // When the packet was received, by the kernel if KernelTimestamp is true, else when it was read from the socket.
+	KernelTimestamp bool        // True if Timestamp was set by the kernel on reception (SO_TIMESTAMPNS, only available on Linux).
+	Destination     net.IP      // The destination address of the packet (multicast, unicast or broadcast). Not available on Windows.
+	InterfaceIndex  int         // Index of the network interface the packet was received on. Not available on Windows.
+	Size            int         // Size of the UDP datagram in bytes, at most [packet.MAX_PACKET_SIZE] (the size of the read buffer).
+	Truncated       bool        // True if the datagram was larger than [packet.MAX_PACKET_SIZE] and Size is not its real size. Only reported on Linux.
+}
+
+// Information found in the control messages of a received packet, see parseControlMessages.
+type controlInfo struct {
+	timestamp    time.Time
+	hasTimestamp bool
+	overflows    uint32 // cumulative number of packets dropped by the receive queue of the socket
+	hasOverflows bool
 }
 
 // PacketCallbackFunc is the function type to be used with [Receiver.RegisterPacketCallback].
@@ -42,14 +58,37 @@ type TerminationCallbackFunc func(universe uint16)
 // A sACN Receiver. Use [NewReceiver] to create a receiver.
 type Receiver struct {
 	conn *ipv4.PacketConn
+	udp  *net.UDPConn // same socket as conn, for reading control messages not supported by ipv4
 	itf  *net.Interface
 	stop chan bool
 
 	lastPackets      map[uint16]networkPacket
 	streamTerminated map[uint16]bool
+	sourceNames      map[packet.CID]string // last known source name of the accepted sources, for filtering packets without one
+	sources          *sourceTracker
+	stats            *lossTracker
+
+	filterMu        sync.RWMutex
+	filter          *SourceFilter
+	universeFilters map[uint16]*SourceFilter
 
 	packetCallbacks     map[packet.SACNPacketType]PacketCallbackFunc
 	terminationCallback TerminationCallbackFunc
+	sourceEventCallback SourceEventCallbackFunc
+	dispatcher          *dispatcher
+	options             ReceiverOptions
+	logger              *slog.Logger
+
+	errMu sync.Mutex
+	err   error // error which stopped the receive loop
+}
+
+// Optional arguments for [NewReceiver].
+type ReceiverOptions struct {
+	QueueSize      int            // Number of callbacks which can be queued per universe before the DispatchPolicy applies. Defaults to [DEFAULT_DISPATCH_QUEUE_SIZE].
+	DispatchPolicy DispatchPolicy // What to do when the callbacks of a universe fall behind. Defaults to [DispatchDropOldest].
+	Logger         *slog.Logger   // Optionally use an alternative logger instead of [slog.Default].
+	Socket         SocketOptions  // Tuning of the socket of the receiver (eg: a larger ReceiveBufferSize for many universes).
 }
 
 type networkPacket struct {
@@ -57,9 +96,17 @@ type networkPacket struct {
 	packet packet.SACNPacket
 }
 
-// NewReceiver creates a new receiver bound to the provided interface
-func NewReceiver(itf *net.Interface) (*Receiver, error) {
+// NewReceiver creates a new receiver bound to the provided interface.
+// options can be nil to use the defaults.
+func NewReceiver(itf *net.Interface, options *ReceiverOptions) (*Receiver, error) {
 	r := &Receiver{}
+	if options != nil {
+		r.options = *options
+	}
+	r.logger = r.options.Logger
+	if r.logger == nil {
+		r.logger = slog.Default()
+	}
 
 	addr := fmt.Sprintf(":%d", SACN_PORT)
 	listener, err := reuseport.ListenPacket("udp4", addr)
@@ -67,12 +114,23 @@ func NewReceiver(itf *net.Interface) (*Receiver, error) {
 		return nil, err
 	}
 	udpConn := listener.(*net.UDPConn)
+	err = r.options.Socket.apply(udpConn)
+	if err != nil {
+		udpConn.Close()
+		return nil, err
+	}
+	r.udp = udpConn
 	r.conn = ipv4.NewPacketConn(udpConn)
-	r.conn.SetControlMessage(ipv4.FlagDst, true) // Do not catch error if running on windows
+	r.conn.SetControlMessage(ipv4.FlagDst|ipv4.FlagInterface, true) // Do not catch error if running on windows
+	enableControlMessages(udpConn)
 	r.itf = itf
 
 	r.lastPackets = make(map[uint16]networkPacket)
 	r.streamTerminated = make(map[uint16]bool)
+	r.sourceNames = make(map[packet.CID]string)
+	r.universeFilters = make(map[uint16]*SourceFilter)
+	r.sources = newSourceTracker()
+	r.stats = newLossTracker()
 	r.packetCallbacks = make(map[packet.SACNPacketType]PacketCallbackFunc)
 
 	return r, nil
@@ -82,8 +140,20 @@ func NewReceiver(itf *net.Interface) (*Receiver, error) {
 func (r *Receiver) Start() {
 
 	r.stop = make(chan bool)
-
-	go r.recvLoop()
+	r.dispatcher = newDispatcher(r.options.QueueSize, r.options.DispatchPolicy)
+	r.errMu.Lock()
+	r.err = nil
+	r.errMu.Unlock()
+
+	go func() {
+		err := r.recvLoop()
+		if err != nil {
+			r.logger.Error("Receiver stopped", slog.Any("error", err))
+		}
+		r.errMu.Lock()
+		r.err = err
+		r.errMu.Unlock()
+	}()
 }
 
 // Stops the receiver
@@ -91,16 +161,23 @@ func (r *Receiver) Stop() {
 	close(r.stop)
 }
 
+// Err returns the error which stopped the receiver, or nil if it is running or was stopped with [Receiver.Stop].
+func (r *Receiver) Err() error {
+	r.errMu.Lock()
+	defer r.errMu.Unlock()
+	return r.err
+}
+
 // JoinUniverse starts listening for packets sent on the provided universe.
 // Universe number shall be in the range 1 to 63999.
 // Joins the multicast group associated with the universe number.
 func (r *Receiver) JoinUniverse(universe uint16) error {
 	if universe == 0 || (universe > 64000 && universe != DISCOVERY_UNIVERSE) { // Section 9.1.1 of ANSI E1.31—2018
-		return errors.New(fmt.Sprintf("Invalid universe number: %d\n", universe))
+		return &UniverseError{Universe: universe, Err: ErrInvalidUniverse}
 	}
 	err := r.conn.JoinGroup(r.itf, universeToAddress(universe))
 	if err != nil {
-		return errors.New(fmt.Sprintf("Could not join multicast group for universe %v: %v", universe, err))
+		return fmt.Errorf("Could not join multicast group for universe %v: %w", universe, err)
 	}
 	return nil
 }
@@ -110,13 +187,17 @@ func (r *Receiver) JoinUniverse(universe uint16) error {
 func (r *Receiver) LeaveUniverse(universe uint16) error {
 	err := r.conn.LeaveGroup(r.itf, universeToAddress(universe))
 	if err != nil {
-		return errors.New(fmt.Sprintf("Could not leave multicast group for universe %v: %v", universe, err))
+		return fmt.Errorf("Could not leave multicast group for universe %v: %w", universe, err)
 	}
 	return nil
 }
 
 // RegisterPacketCallback registers a callback of type PacketCallbackFunc.
-// The callback will be triggered on reception of a new packet of type [packet.SACNPacketType] on any universe
+// The callback will be triggered on reception of a new packet of type [packet.SACNPacketType] on any universe.
+//
+// Callbacks of a universe are run one at a time, in the order packets were received.
+// Callbacks of different universes run in parallel. See [ReceiverOptions] for what happens when a callback is too slow.
+// A [packet.SyncPacket] callback runs after the callbacks of the DataPackets it synchronises, received before it on the member universes.
 func (r *Receiver) RegisterPacketCallback(packetType packet.SACNPacketType, callback PacketCallbackFunc) {
 	r.packetCallbacks[packetType] = callback
 }
@@ -130,49 +211,109 @@ func (r *Receiver) RegisterTerminationCallback(callback TerminationCallbackFunc)
 	r.terminationCallback = callback
 }
 
-func (r *Receiver) recvLoop() {
+// RegisterSourceEventCallback registers a callback for changes in the lifecycle of each source sending data on a universe.
+// See [SourceEventType] for the possible events.
+// Events are run in order with the packet callbacks of the same universe.
+func (r *Receiver) RegisterSourceEventCallback(callback SourceEventCallbackFunc) {
+	r.sourceEventCallback = callback
+}
+
+// DroppedCallbacks returns the number of callbacks discarded by the [DispatchPolicy] since the receiver was started.
+func (r *Receiver) DroppedCallbacks() uint64 {
+	if r.dispatcher == nil {
+		return 0
+	}
+	return r.dispatcher.dropped.Load()
+}
+
+// SetFilter sets the [SourceFilter] applied to packets on all universes. Use nil to remove it.
+// Packets rejected by a filter are discarded before any callback, stream termination or data loss handling.
+// The filter can be changed at any time, but shall not be modified once set. Call SetFilter again instead.
+func (r *Receiver) SetFilter(filter *SourceFilter) {
+	r.filterMu.Lock()
+	defer r.filterMu.Unlock()
+	r.filter = filter
+}
+
+// SetUniverseFilter sets the [SourceFilter] applied to packets of a universe, in addition to the one set with [Receiver.SetFilter].
+// Use nil to remove it. Sync packets are filtered on their sync address and discovery packets on [DISCOVERY_UNIVERSE].
+func (r *Receiver) SetUniverseFilter(universe uint16, filter *SourceFilter) {
+	r.filterMu.Lock()
+	defer r.filterMu.Unlock()
+	if filter == nil {
+		delete(r.universeFilters, universe)
+		return
+	}
+	r.universeFilters[universe] = filter
+}
+
+func (r *Receiver) accepts(universe uint16, cid packet.CID, name string, info PacketInfo) bool {
+	r.filterMu.RLock()
+	defer r.filterMu.RUnlock()
+
+	return r.filter.accepts(cid, info.Source.IP, name) && r.universeFilters[universe].accepts(cid, info.Source.IP, name)
+}
+
+func (r *Receiver) recvLoop() error {
 	defer r.conn.Close()
+	defer r.dispatcher.close()
 
+	buf := make([]byte, packet.MAX_PACKET_SIZE) // reused for every packet, unmarshalling copies what it keeps
+	oob := make([]byte, 256)                    // space for the packet info and timestamp control messages
 	for {
 		select {
 		case <-r.stop:
-			return
+			return nil
 		default:
-			buf := make([]byte, 1144) // 1144 bytes is max packet size (full DiscoveryPacket)
-
 			err := r.conn.SetDeadline(time.Now().Add(time.Millisecond * NETWORK_DATA_LOSS_TIMEOUT))
 			if err != nil {
-				log.Panic(fmt.Sprintf("Could not set deadline on socket: %v", err))
-				return
+				return fmt.Errorf("Could not set deadline on socket: %w", err)
 			}
 
-			n, cm, addr, _ := r.conn.ReadFrom(buf)
-			if addr == nil { // timeout
-				r.checkTimeouts()
-				continue
-			}
-
-			// fmt.Printf("Received %d bytes from %s\n", n, source.String())
-			var p packet.SACNPacket
-			p, err = packet.Unmarshal(buf[:n])
+			n, oobn, flags, addr, err := r.udp.ReadMsgUDP(buf, oob)
 			if err != nil {
-				continue
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					r.checkTimeouts()
+					continue
+				}
+				return fmt.Errorf("Could not read from socket: %w", err)
 			}
 
-			mode := PacketUnknown
-			if cm != nil && cm.Dst != nil {
+			info := PacketInfo{
+				Source:    *addr,
+				Mode:      PacketUnknown,
+				Size:      n,
+				Truncated: isTruncated(flags),
+			}
+			control := parseControlMessages(oob[:oobn])
+			info.Timestamp, info.KernelTimestamp = control.timestamp, control.hasTimestamp
+			if !info.KernelTimestamp {
+				info.Timestamp = time.Now()
+			}
+			if control.hasOverflows {
+				r.stats.setOverflowCounter(control.overflows)
+			}
+			cm := new(ipv4.ControlMessage)
+			if cm.Parse(oob[:oobn]) == nil && cm.Dst != nil {
+				info.Destination = cm.Dst
+				info.InterfaceIndex = cm.IfIndex
 				if cm.Dst.Equal(net.IPv4bcast) { // Only handle local broadcast for now (ie: 255.255.255.255) not directed broadcast (ie: 192.168.1.255/24)
-					mode = PacketBroadcast
+					info.Mode = PacketBroadcast
 				} else if cm.Dst.IsMulticast() {
-					mode = PacketMulticast
+					info.Mode = PacketMulticast
 				} else {
-					mode = PacketUnicast
+					info.Mode = PacketUnicast
 				}
 			}
 
-			info := PacketInfo{
-				Source: *addr.(*net.UDPAddr),
-				Mode:   mode,
+			var p packet.SACNPacket
+			p, err = packet.Unmarshal(buf[:n])
+			if err != nil {
+				if r.logger.Enabled(context.Background(), slog.LevelDebug) { // avoid building the attributes on the receive path
+					r.logger.Debug("Invalid packet", slog.String("source", addr.String()), slog.Any("error", err))
+				}
+				continue
 			}
 
 			r.handlePacket(p, info)
@@ -185,14 +326,51 @@ func (r *Receiver) handlePacket(p packet.SACNPacket, info PacketInfo) {
 	r.checkTimeouts()
 	packetType := p.GetType()
 
+	var universe uint16
+	var cid packet.CID
+	var name string
 	switch packetType {
 	case packet.PacketTypeData:
 		d, _ := p.(*packet.DataPacket)
-		r.storeLastPacket(d.Universe, d)
-		if d.IsStreamTerminated() { // Bit 6: Stream Terminated
+		universe = d.Universe
+		cid = d.CID
+		name = d.GetSourceName()
+	case packet.PacketTypeSync:
+		s, _ := p.(*packet.SyncPacket)
+		universe = s.SyncAddress
+		cid = s.CID
+		name = r.sourceNames[cid]
+	case packet.PacketTypeDiscovery:
+		d, _ := p.(*packet.DiscoveryPacket)
+		universe = DISCOVERY_UNIVERSE
+		cid = d.CID
+		name = d.GetSourceName()
+	}
+	if !r.accepts(universe, cid, name, info) {
+		if r.logger.Enabled(context.Background(), slog.LevelDebug) {
+			r.logger.Debug("Filtered packet",
+				slog.Int("universe", int(universe)),
+				slog.String("cid", cid.String()),
+				slog.String("source", info.Source.IP.String()),
+				slog.String("packet_type", packetTypeName(packetType)),
+			)
+		}
+		return
+	}
+
+	switch packetType {
+	case packet.PacketTypeData:
+		d, _ := p.(*packet.DataPacket)
+		r.stats.update(d)
+		r.sourceNames[cid] = name
+		if event, ok := r.sources.update(d, info.Source, time.Now()); ok {
+			r.sendSourceEvent(event)
+		}
+		if d.IsStreamTerminated() { // Bit 6: Stream Terminated, sent 3 times by the source
 			r.terminateUniverse(d.Universe)
 			return
 		}
+		r.storeLastPacket(d.Universe, d)
 		if d.SyncAddress > 0 {
 			_, ok := r.streamTerminated[d.SyncAddress]
 			if !ok { // only join sync universe if not already
@@ -205,9 +383,25 @@ func (r *Receiver) handlePacket(p packet.SACNPacket, info PacketInfo) {
 	}
 
 	callback := r.packetCallbacks[packetType]
-	if callback != nil {
-		go callback(p, info)
+	if callback == nil {
+		return
+	}
+	if packetType == packet.PacketTypeSync { // synchronise the data already received on the members
+		r.dispatcher.dispatchAfter(universe, r.syncMembers(universe), func() { callback(p, info) })
+		return
+	}
+	r.dispatcher.dispatch(universe, func() { callback(p, info) })
+}
+
+// syncMembers returns the universes whose last DataPacket is synchronised by the sync universe.
+func (r *Receiver) syncMembers(syncUniverse uint16) []uint16 {
+	var members []uint16
+	for universe, last := range r.lastPackets {
+		if d, ok := last.packet.(*packet.DataPacket); ok && d.SyncAddress == syncUniverse && !r.streamTerminated[universe] {
+			members = append(members, universe)
+		}
 	}
+	return members
 }
 
 func (r *Receiver) storeLastPacket(universe uint16, p packet.SACNPacket) {
@@ -219,6 +413,10 @@ func (r *Receiver) storeLastPacket(universe uint16, p packet.SACNPacket) {
 }
 
 func (r *Receiver) checkTimeouts() {
+	for _, event := range r.sources.expire(time.Now()) {
+		r.stats.forget(event.Universe, event.CID)
+		r.sendSourceEvent(event)
+	}
 	for universe, last := range r.lastPackets {
 		if time.Since(last.ts) > time.Millisecond*NETWORK_DATA_LOSS_TIMEOUT {
 			r.terminateUniverse(universe)
@@ -227,8 +425,34 @@ func (r *Receiver) checkTimeouts() {
 }
 
 func (r *Receiver) terminateUniverse(universe uint16) {
-	if r.terminationCallback != nil && !r.streamTerminated[universe] {
-		go r.terminationCallback(universe)
-		r.streamTerminated[universe] = true
+	if r.streamTerminated[universe] {
+		return
+	}
+	r.streamTerminated[universe] = true
+	r.logger.Info("Universe terminated", slog.Int("universe", int(universe)))
+	if r.terminationCallback != nil {
+		callback := r.terminationCallback
+		r.dispatcher.dispatchControl(universe, func() { callback(universe) })
+	}
+	r.dispatcher.release(universe)
+}
+
+func (r *Receiver) sendSourceEvent(event SourceEvent) {
+	if event.Type == SourceTerminated || event.Type == SourceTimedOut {
+		if !r.sources.isActive(event.CID) { // the source does not send on other universes
+			delete(r.sourceNames, event.CID)
+		}
+	}
+	if r.logger.Enabled(context.Background(), slog.LevelDebug) {
+		r.logger.Debug("Source "+event.Type.String(),
+			slog.Int("universe", int(event.Universe)),
+			slog.String("cid", event.CID.String()),
+			slog.String("source", event.Source.IP.String()),
+			slog.String("source_name", event.SourceName),
+		)
+	}
+	if r.sourceEventCallback != nil {
+		callback := r.sourceEventCallback
+		r.dispatcher.dispatchControl(event.Universe, func() { callback(event) })
 	}
 }
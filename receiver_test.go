@@ -0,0 +1,19 @@
+package sacn
+
+import (
+	"testing"
+)
+
+// newTestReceiver returns a receiver whose packets are handled by calling handlePacket directly, without starting it.
+func newTestReceiver(t *testing.T) *Receiver {
+	r, err := NewReceiver(nil, nil)
+	if err != nil {
+		t.Fatalf("Could not create receiver: %v", err)
+	}
+	r.dispatcher = newDispatcher(0, DispatchDropOldest) // only created by Start
+	t.Cleanup(func() {
+		r.dispatcher.close()
+		r.udp.Close()
+	})
+	return r
+}
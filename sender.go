@@ -1,14 +1,15 @@
 package sacn
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/google/uuid"
 	"gitlab.com/patopest/go-sacn/packet"
 )
 
@@ -16,124 +17,290 @@ import (
 type Sender struct {
 	conn *net.UDPConn
 
-	universes map[uint16]*senderUniverse
-	discovery *senderUniverse
-	wg        sync.WaitGroup
-	logger    *log.Logger
+	mu           sync.RWMutex // protects universes and syncGroups
+	universes    map[uint16]*senderUniverse
+	syncGroups   map[uint16]*syncGroup
+	discoveryUni *senderUniverse
+	wg           sync.WaitGroup
+	closeOnce    sync.Once
+	closed       chan struct{} // closed once all universes are terminated and the socket is closed
+
+	parent    *Sender // sender whose socket is shared, nil if this is not a source
+	sourcesMu sync.Mutex
+	sources   []*Sender // sources sharing the socket of this sender
+	closing   bool      // no sources can be added once the sender is closing
+	logger    *slog.Logger
+
+	syncMu        sync.Mutex       // protects syncSequences
+	syncSequences map[uint16]uint8 // sequence of the SyncPackets sent on each sync address
+
+	healthMu      sync.Mutex // protects the health of destinations and the error callback
+	health        map[string]*DestinationStatus
+	errorCallback SendErrorCallbackFunc
 
 	// common options for packets
-	cid        [16]byte
-	sourceName string
-	keepAlive  time.Duration
+	cid               packet.CID
+	sourceName        string // protected by mu
+	priority          uint8
+	keepAlive         time.Duration
+	discoveryInterval time.Duration // interval of universe discovery, protected by mu, 0 if disabled
+	frameRate         float64
+	queueSize         int
+	backpressure      BackpressurePolicy
+	priorityInterval  time.Duration // interval of per-address priority packets, see PER_ADDRESS_PRIORITY_INTERVAL
 }
 
 // Optional arguments for [NewSender] to be applied to all packets being sent by the sender.
 // These can be overridden on a per packet basis if set in the [packet.SACNPacket] being sent.
 type SenderOptions struct {
-	CID        [16]byte    // the CID (Component Identifier): a RFC4122 compliant UUID.
-	SourceName string      // A source name (must not be longer than 64 characters)
-	Logger     *log.Logger // Optionally use an alternative logger instead of the default.
-	// KeepAlive  time.Duration
+	CID               packet.CID         // the CID (Component Identifier): a RFC4122 compliant UUID. Defaults to a new random CID, use [LoadOrCreateCID] or [NewNameCID] to keep it across restarts.
+	SourceName        string             // A source name (must not be longer than 64 characters). See [Sender.SetSourceName].
+	Priority          *uint8             // Priority of the universes (0 to 200). Defaults to [packet.DEFAULT_PRIORITY] when nil. See [Sender.SetPriority].
+	Logger            *slog.Logger       // Optionally use an alternative logger instead of [slog.Default].
+	KeepAlive         time.Duration      // Interval at which the last DataPacket of a universe is sent again when nothing new was sent. Defaults to [DEFAULT_KEEP_ALIVE_INTERVAL], use a negative value to disable.
+	MaxFrameRate      float64            // Maximum number of DataPackets per second sent on each universe (eg: [DMX_FRAME_RATE]). Defaults to 0 (unlimited). See [Sender.SetMaxFrameRate].
+	QueueSize         int                // Number of packets which can be queued per universe before the Backpressure policy applies. Defaults to [DEFAULT_SEND_QUEUE_SIZE].
+	DiscoveryInterval time.Duration      // Interval at which the universes of the sender are advertised. Defaults to [UNIVERSE_DISCOVERY_INTERVAL] seconds, use a negative value to disable. See [Sender.SetDiscoveryInterval].
+	Socket            SocketOptions      // Tuning of the socket of the sender, shared with its sources.
+	Backpressure      BackpressurePolicy // What to do when packets are written to a universe faster than they can be sent. Defaults to [BackpressureBlock].
 }
 
 // Stores all the information required per universe a sender is handling
 type senderUniverse struct {
-	number       uint16
-	dataCh       chan packet.SACNPacket
-	enabled      bool
-	sequence     uint8
-	multicast    bool
-	destinations []net.UDPAddr
+	number uint16
+	dataCh chan packet.SACNPacket
+	queue  *sendQueue // packets received on dataCh, waiting to be sent
+
+	txMu     sync.Mutex // protects the transmission state below, which is shared with sync frames
+	sequence uint8
+	last     *packet.DataPacket // copy of the last level data (START_CODE_NULL) sent, for keep-alive
+	lastSent time.Time
+	pending  *packet.DataPacket // latest DataPacket held back by the frame rate limit
+
+	notify  chan struct{} // wakes up the send loop when settings changed
+	stopMu  sync.RWMutex  // held by Send while writing to dataCh, so it is not closed meanwhile
+	stopped atomic.Bool   // set once the universe is stopped, it is then no longer found by getUniverse
+	done    chan struct{} // closed once the universe is terminated
+
+	mu                sync.Mutex // protects the settings below, which can be changed while sending
+	enabled           bool       // false once the universe is terminating
+	multicast         bool
+	destinations      []net.UDPAddr // replaced, never modified in place, as they are used without holding mu
+	maxRate           float64
+	priorities        []byte // per-address priorities (0xDD start code), nil if not used
+	prioritiesChanged bool
+	levels            [512]byte // current state of the universe, see Sender.SetSlot
+	levelsChanged     bool
+	fades             [512]slotFade
+	fading            int    // number of slots currently fading
+	sourceName        string // empty to use the sender's source name
+	priority          uint8
+	preview           bool
+	syncAddress       uint16
+	settingsChanged   bool
+	discoveryExcluded bool // not advertised in universe discovery
+	paused            bool // nothing is sent while paused, see Sender.PauseUniverse
+	blackout          bool // levels are sent as 0, see Sender.SetBlackout
 }
 
-var universeNotFoundError = errors.New("Universe is not initialised, please use StartUniverse() first")
+// Buffers packets are marshalled into before being sent, shared by all universes to avoid an allocation per packet
+var packetBuffers = sync.Pool{
+	New: func() any { return new([packet.MAX_PACKET_SIZE]byte) },
+}
 
 // NewSender creates a new [Sender]. Optionally pass a bind string of the host's ip address it should bind to (eg: "192.168.1.100").
 // This is mandatory if multicast is being used on any universe.
 func NewSender(address string, options *SenderOptions) (*Sender, error) {
+	err := options.setDefaults()
+	if err != nil {
+		return nil, err
+	}
 
-	// Generate RFC 4122 compliant UUID. From ANSI E1.31-2019 Section 5.6
-	cid, err := uuid.NewV7()
+	server, err := net.ResolveUDPAddr("udp", net.JoinHostPort(address, "0"))
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp", server)
 	if err != nil {
 		return nil, err
 	}
+	err = options.Socket.apply(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return newSender(conn, options), nil
+}
 
-	if options.CID[0] == 0 {
-		bytes, _ := cid.MarshalBinary()
-		copy(options.CID[:], bytes[:16])
+// Validates the options and fills in the defaults of the options which are not set
+func (options *SenderOptions) setDefaults() error {
+	if options.CID.IsZero() {
+		// Generate RFC 4122 compliant UUID. From ANSI E1.31-2019 Section 5.6
+		cid, err := NewCID()
+		if err != nil {
+			return err
+		}
+		options.CID = cid
 	}
 	if options.SourceName == "" {
 		options.SourceName = "gitlab.com/patopest/go-sacn"
 	}
 	if len(options.SourceName) > 64 {
-		return nil, errors.New("Source name is too long. Maximum is 64 bytes")
+		return ErrSourceNameTooLong
 	}
 	if options.Logger == nil {
-		options.Logger = log.Default()
+		options.Logger = slog.Default()
 	}
-	// if options.KeepAlive == 0 {
-	// 	options.KeepAlive = 1 * time.Second
-	// }
-
-	server, err := net.ResolveUDPAddr("udp", address+":0")
-	if err != nil {
-		return nil, err
+	if options.Priority == nil {
+		priority := uint8(packet.DEFAULT_PRIORITY)
+		options.Priority = &priority
 	}
-	conn, err := net.ListenUDP("udp", server)
-	if err != nil {
-		return nil, err
+	if *options.Priority > 200 {
+		return ErrInvalidPriority
 	}
+	if options.DiscoveryInterval == 0 {
+		options.DiscoveryInterval = UNIVERSE_DISCOVERY_INTERVAL * time.Second
+	} else if options.DiscoveryInterval < 0 {
+		options.DiscoveryInterval = 0
+	}
+	if options.KeepAlive == 0 {
+		options.KeepAlive = DEFAULT_KEEP_ALIVE_INTERVAL * time.Millisecond
+	}
+	return nil
+}
 
+// Creates a sender sending on conn and starts its discovery
+func newSender(conn *net.UDPConn, options *SenderOptions) *Sender {
 	s := &Sender{
-		conn:       conn,
-		universes:  make(map[uint16]*senderUniverse),
-		cid:        options.CID,
-		sourceName: options.SourceName,
-		logger:     options.Logger,
-		// keepAlive:  options.KeepAlive,
+		conn:              conn,
+		universes:         make(map[uint16]*senderUniverse),
+		syncGroups:        make(map[uint16]*syncGroup),
+		syncSequences:     make(map[uint16]uint8),
+		health:            make(map[string]*DestinationStatus),
+		closed:            make(chan struct{}),
+		cid:               options.CID,
+		sourceName:        options.SourceName,
+		priority:          *options.Priority,
+		logger:            options.Logger,
+		keepAlive:         options.KeepAlive,
+		frameRate:         options.MaxFrameRate,
+		queueSize:         options.QueueSize,
+		backpressure:      options.Backpressure,
+		discoveryInterval: options.DiscoveryInterval,
+		priorityInterval:  PER_ADDRESS_PRIORITY_INTERVAL * time.Millisecond,
+		discoveryUni: &senderUniverse{
+			number:    DISCOVERY_UNIVERSE,
+			enabled:   true,
+			multicast: true,
+			dataCh:    make(chan packet.SACNPacket, 0), // still create a data channel to close on sender Close()
+			notify:    make(chan struct{}, 1),
+		},
 	}
 
+	s.wg.Add(1)
 	go s.sendDiscoveryLoop()
 
-	return s, nil
+	return s
 }
 
-// Stops the sender and all initialised universes
+// Stops the sender and all initialised universes, including the ones of its sources (see [Sender.NewSource]).
+// It returns once the termination packets of all universes were sent. See [Sender.Shutdown] to limit how long it waits.
 func (s *Sender) Close() {
+	s.Shutdown(context.Background())
+}
 
+// Shutdown stops the sender and all initialised universes, including the ones of its sources (see [Sender.NewSource]),
+// waiting until the termination packets of all universes were sent or the context is done, in which case the context's error is returned.
+// The socket of the sender is closed once all universes are terminated.
+//
+// Shutting down a source only stops its own universes, the socket is kept open for the other sources.
+func (s *Sender) Shutdown(ctx context.Context) error {
+	s.stop()
+
+	select {
+	case <-s.closed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stops all the universes of the sender and its sources. s.closed is closed once they are all terminated.
+func (s *Sender) stop() {
+	s.sourcesMu.Lock()
+	s.closing = true
+	sources := slices.Clone(s.sources)
+	s.sourcesMu.Unlock()
+	for _, source := range sources {
+		source.stop()
+	}
+
+	s.mu.RLock()
 	for _, uni := range s.universes {
-		if uni.enabled {
-			close(uni.dataCh)
-		}
+		uni.stop()
 	}
-	close(s.discovery.dataCh)
-	s.wg.Wait()
-	defer s.conn.Close()
+	s.mu.RUnlock()
+	s.closeOnce.Do(func() {
+		close(s.discoveryUni.dataCh)
+		go func() {
+			s.wg.Wait()
+			if s.parent != nil {
+				s.parent.removeSource(s)
+			} else {
+				for _, source := range sources { // the socket is shared with the sources
+					<-source.closed
+				}
+				s.conn.Close()
+			}
+			close(s.closed)
+		}()
+	})
 }
 
 // StartUniverse initialises a new universe to be sent by the sender.
 // It returns a channel into which [packet.SACNPacket] can be written to for sending out on the network.
 // Optionally you can use [Sender.Send] to also send packets for a universe.
+//
+// Packets written to the channel are queued until they can be sent. When the queue is full,
+// the Backpressure policy of [SenderOptions] applies (see [Sender.SetBackpressure]).
 func (s *Sender) StartUniverse(universe uint16) (chan<- packet.SACNPacket, error) {
 	if s.IsEnabled(universe) == true {
-		return nil, errors.New("Universe is already enabled")
+		return nil, &UniverseError{Universe: universe, Err: ErrUniverseAlreadyStarted}
 	}
 	if universe < 1 || universe >= 64000 { // From ANSI E1.31-2019 Section 6.2.7
-		return nil, errors.New("Universe value is incorrect, should be between 1 and 63999")
+		return nil, &UniverseError{Universe: universe, Err: ErrInvalidUniverse}
 	}
 
-	ch := make(chan packet.SACNPacket, 3)
+	ch := make(chan packet.SACNPacket)
 	uni := &senderUniverse{
 		number:       universe,
 		enabled:      true,
 		dataCh:       ch,
+		queue:        newSendQueue(s.queueSize, s.backpressure),
 		sequence:     0,
 		multicast:    false,
 		destinations: make([]net.UDPAddr, 0),
+		maxRate:      s.frameRate,
+		priority:     s.priority,
+		notify:       make(chan struct{}, 1),
+		done:         make(chan struct{}),
 	}
+	s.mu.Lock()
 	s.universes[universe] = uni
+	s.mu.Unlock()
+	s.discoveryUni.wake()
 
-	go s.sendLoop(universe)
+	// Move packets from the channel to the queue, applying the backpressure policy
+	go func() {
+		for p := range ch {
+			uni.queue.push(p)
+		}
+		uni.queue.close()
+	}()
+
+	s.wg.Add(1)
+	go s.sendLoop(uni)
 
 	return ch, nil
 }
@@ -141,217 +308,503 @@ func (s *Sender) StartUniverse(universe uint16) (chan<- packet.SACNPacket, error
 // StopUniverse stops sending packet for a universe.
 // This closes the channel returned to by [Sender.StartUniverse].
 // On closing, 3 [packet.DataPacket] will be sent out with the StreamTerminated bit set as specified in section 6.7.1 of ANSI E1.31—2018.
+// It does not wait for them to be sent, see [Sender.StopUniverseAndWait].
+// The universe is then unknown to the other methods, which return [ErrUniverseNotFound], and it can be started again.
 func (s *Sender) StopUniverse(universe uint16) error {
 
-	uni, exists := s.universes[universe]
+	uni, exists := s.getUniverse(universe)
 	if exists {
-		close(uni.dataCh)
+		uni.stop()
+		return nil
+	}
+	return universeNotFound(universe)
+}
+
+// StopUniverseAndWait stops sending packets for a universe like [Sender.StopUniverse],
+// and waits until its termination packets were sent or the context is done, in which case the context's error is returned.
+func (s *Sender) StopUniverseAndWait(ctx context.Context, universe uint16) error {
+	uni, exists := s.getUniverse(universe)
+	if !exists {
+		return universeNotFound(universe)
+	}
+	uni.stop()
+
+	select {
+	case <-uni.done:
 		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	return universeNotFoundError
 }
 
 // Send a packet on a universe.
 // This is an alternative way to writing packets directly on the channel returned by [Sender.StartUniverse]
+//
+// The last [packet.DataPacket] sent on a universe is repeated at the KeepAlive interval of [SenderOptions] until a new one is sent.
+// If a maximum frame rate is set, only the latest [packet.DataPacket] received within a frame period is sent.
 func (s *Sender) Send(universe uint16, p packet.SACNPacket) error {
-	uni, exists := s.universes[universe]
+	uni, exists := s.getUniverse(universe)
 	if exists {
-		uni.dataCh <- p
-		return nil
+		uni.stopMu.RLock()
+		defer uni.stopMu.RUnlock()
+		if !uni.stopped.Load() { // stopped since it was found
+			uni.dataCh <- p
+			return nil
+		}
 	}
-	return universeNotFoundError
+	return universeNotFound(universe)
 }
 
-func (s *Sender) sendLoop(universe uint16) {
+func (s *Sender) sendLoop(uni *senderUniverse) {
+	defer s.wg.Done()
 
-	uni := s.universes[universe]
-	s.wg.Add(1)
-	ch := uni.dataCh
-
-	// Receive new packets to send out
-	for p := range ch {
-		uni.sequence += 1
-		sequence := uni.sequence
-
-		packetType := p.GetType()
-		switch packetType {
-		case packet.PacketTypeData:
-			d, _ := p.(*packet.DataPacket)
-			if d.CID[0] == 0 {
-				d.CID = s.cid
+	// Only send keep-alive packets when nothing new was sent for the keep-alive interval
+	var keepAlive *time.Timer
+	var keepAliveCh <-chan time.Time
+	if s.keepAlive > 0 {
+		keepAlive = time.NewTimer(s.keepAlive)
+		defer keepAlive.Stop()
+		keepAliveCh = keepAlive.C
+	}
+
+	// Fires when the next frame can be sent while a DataPacket is held back by the frame rate limit
+	frame := time.NewTimer(time.Hour)
+	frame.Stop()
+	defer frame.Stop()
+	var frameCh <-chan time.Time
+
+	// Fires when the per-address priorities of the universe are due
+	priority := time.NewTimer(time.Hour)
+	priority.Stop()
+	defer priority.Stop()
+	var priorityCh <-chan time.Time
+
+	// Output clock of the fades, ticking at the frame rate of the universe while slots are fading
+	fade := time.NewTicker(time.Hour)
+	fade.Stop()
+	defer fade.Stop()
+	var fadeCh <-chan time.Time
+
+	queue := func(d *packet.DataPacket) {
+		wait := s.queueData(uni, d)
+		if wait > 0 && frameCh == nil {
+			resetTimer(frame, wait)
+			frameCh = frame.C
+		}
+	}
+
+loop:
+	for {
+		select {
+		case <-uni.queue.ready: // Receive new packets to send out
+			for {
+				p, ok := uni.queue.pop()
+				if !ok {
+					break
+				}
+				if d := s.handlePacket(uni, p); d != nil {
+					queue(d)
+				}
+			}
+			if uni.queue.done() {
+				break loop
 			}
-			d.Universe = universe
-			d.Sequence = sequence // increment sequence number
-			if d.GetSourceName() == "" {
-				d.SetSourceName(s.sourceName)
+		case <-frameCh:
+			frameCh = nil
+			s.flushData(uni)
+		case <-keepAliveCh:
+			keepAlive.Reset(s.sendKeepAlive(uni))
+		case <-uni.notify: // levels or settings changed
+			if fadeCh == nil && uni.stepFades(time.Now()) {
+				fade.Reset(uni.fadeInterval())
+				fadeCh = fade.C
+			}
+			if d := s.levelsPacket(uni); d != nil {
+				queue(d)
+			}
+			if uni.takeSettingsChanged() {
+				if d := uni.lastData(); d != nil { // send the new settings right away
+					queue(d)
+				}
+			}
+			if uni.takePrioritiesChanged() {
+				if s.sendPriorities(uni) {
+					resetTimer(priority, s.priorityInterval)
+					priorityCh = priority.C
+				} else {
+					priorityCh = nil
+				}
 			}
-		case packet.PacketTypeSync:
-			d, _ := p.(*packet.SyncPacket)
-			if d.CID[0] == 0 {
-				d.CID = s.cid
+		case now := <-fadeCh:
+			if !uni.stepFades(now) {
+				fade.Stop()
+				fadeCh = nil
 			}
-			d.SyncAddress = universe
-			d.Sequence = sequence
-		case packet.PacketTypeDiscovery: // technically should never have this type of packet here
-			d, _ := p.(*packet.DiscoveryPacket)
-			if d.CID[0] == 0 {
-				d.CID = s.cid
+			if d := s.levelsPacket(uni); d != nil { // fade frames are already paced by the fade clock
+				s.sendNow(uni, d)
 			}
-			if d.GetSourceName() == "" {
-				d.SetSourceName(s.sourceName)
+		case <-priorityCh:
+			if s.sendPriorities(uni) {
+				priority.Reset(s.priorityInterval)
+			} else {
+				priorityCh = nil
 			}
-		default:
-			continue
 		}
-
-		s.sendPacket(uni, p)
 	}
 
+	uni.mu.Lock()
 	uni.enabled = false
-	// Send packet with stream terminated bit 3 times
+	uni.mu.Unlock()
+	s.sendTermination(uni)
+
+	// Destroy universe, unless it was already started again
+	s.mu.Lock()
+	if s.universes[uni.number] == uni {
+		delete(s.universes, uni.number)
+	}
+	s.mu.Unlock()
+	s.discoveryUni.wake()
+	close(uni.done)
+}
+
+// sendTermination sends 3 DataPackets with the Stream_Terminated option set, as specified in section 6.7.1 of ANSI E1.31—2018.
+// They carry the last level data (START_CODE_NULL) of the universe, or only the Start Code if none was sent, with increasing sequence numbers.
+// A DataPacket held back by the frame rate limit is sent first, regardless of the limit, so receivers get the final state.
+func (s *Sender) sendTermination(uni *senderUniverse) {
+	uni.txMu.Lock()
+	defer uni.txMu.Unlock()
+
+	if uni.pending != nil {
+		s.sendData(uni, uni.pending)
+		uni.pending = nil
+	}
+	uni.mu.Lock()
+	uni.paused = false // a paused universe is terminated too
+	uni.mu.Unlock()
 	p := packet.NewDataPacket()
+	if uni.last != nil {
+		*p = *uni.last
+	} else {
+		p.SetData(nil) // the Start Code alone, a DataPacket has at least one property value
+	}
+	p.Universe = uni.number
 	p.SetStreamTerminated(true)
 	for i := 0; i < 3; i++ {
-		s.sendPacket(uni, p)
+		s.sendData(uni, p)
+	}
+}
+
+// Closes the universe's channel, which terminates the universe once all queued packets were sent
+func (uni *senderUniverse) stop() {
+	if uni.stopped.Swap(true) {
+		return
+	}
+	uni.stopMu.Lock()
+	close(uni.dataCh)
+	uni.stopMu.Unlock()
+}
+
+// handlePacket sends a packet received on the universe's channel.
+// DataPackets are returned with the sender's options applied, to be sent at the next frame.
+func (s *Sender) handlePacket(uni *senderUniverse, p packet.SACNPacket) *packet.DataPacket {
+	packetType := p.GetType()
+	switch packetType {
+	case packet.PacketTypeData:
+		return uni.acceptData(p.(*packet.DataPacket))
+	case packet.PacketTypeSync:
+		s.flushData(uni) // data shall be sent before synchronising it
+		d, _ := p.(*packet.SyncPacket)
+		if d.CID.IsZero() {
+			d.CID = s.cid
+		}
+		d.SyncAddress = uni.number
+		d.Sequence = s.nextSyncSequence(uni.number)
+	case packet.PacketTypeDiscovery: // technically should never have this type of packet here
+		d, _ := p.(*packet.DiscoveryPacket)
+		if d.CID.IsZero() {
+			d.CID = s.cid
+		}
+		if d.GetSourceName() == "" {
+			d.SetSourceName(s.GetSourceName())
+		}
+	default:
+		return nil
 	}
 
-	// Destroy universe
-	s.wg.Done()
-	delete(s.universes, universe)
+	s.sendPacket(uni, p)
+	return nil
 }
 
-func (s *Sender) sendDiscoveryLoop() {
+// acceptData returns a copy of a DataPacket written to the universe, as the caller may reuse it.
+// Its level data becomes the state of the universe.
+func (uni *senderUniverse) acceptData(p *packet.DataPacket) *packet.DataPacket {
+	d := *p
+	d.Universe = uni.number
+	if d.GetStartCode() == packet.START_CODE_NULL {
+		uni.storeLevels(&d)
+	}
+	return &d
+}
 
-	s.discovery = &senderUniverse{
-		number:    DISCOVERY_UNIVERSE,
-		enabled:   true,
-		multicast: true,
-		dataCh:    make(chan packet.SACNPacket, 0), // still create a data channel to close on sender Close()
+// queueData sends a DataPacket on a universe, or holds it back if the frame rate limit is reached.
+// Packets received within a frame period are coalesced, only the latest one is kept.
+// It returns how long to wait before calling flushData if the packet was held back.
+func (s *Sender) queueData(uni *senderUniverse, d *packet.DataPacket) time.Duration {
+	uni.txMu.Lock()
+	defer uni.txMu.Unlock()
+
+	wait := uni.frameInterval() - time.Since(uni.lastSent)
+	if uni.pending == nil && wait <= 0 {
+		s.sendData(uni, d)
+		return 0
 	}
-	s.wg.Add(1)
-	timer := time.NewTicker(UNIVERSE_DISCOVERY_INTERVAL * time.Second)
-	defer timer.Stop()
-	defer s.wg.Done()
+	uni.pending = d
+	return wait
+}
 
-	for {
-		select {
-		case <-s.discovery.dataCh: // channel was closed
-			return
-		case <-timer.C:
-			num := len(s.universes)
-			pages := num / 512
-			universes := s.GetUniverses()
-			for page := 0; page < pages+1; page += 1 {
-				p := packet.NewDiscoveryPacket()
-				p.Page = uint8(page)
-				p.Last = uint8(pages)
-				p.CID = s.cid
-				p.SetSourceName(s.sourceName)
-
-				start := page * 512
-				end := (page + 1) * 512
-				if end > len(universes) {
-					end = len(universes)
-				}
-				p.SetUniverses(universes[start:end])
+// flushData sends the DataPacket held back by the frame rate limit, if any.
+func (s *Sender) flushData(uni *senderUniverse) {
+	uni.txMu.Lock()
+	defer uni.txMu.Unlock()
 
-				s.sendPacket(s.discovery, p)
-			}
+	if uni.pending != nil {
+		s.sendData(uni, uni.pending)
+		uni.pending = nil
+	}
+}
+
+// sendNow sends a DataPacket immediately, replacing any DataPacket held back by the frame rate limit.
+func (s *Sender) sendNow(uni *senderUniverse, d *packet.DataPacket) {
+	uni.txMu.Lock()
+	defer uni.txMu.Unlock()
+
+	uni.pending = nil
+	s.sendData(uni, d)
+}
+
+// sendKeepAlive sends the last DataPacket again if nothing was sent for the keep-alive interval.
+// It returns the time until the next keep-alive is due.
+func (s *Sender) sendKeepAlive(uni *senderUniverse) time.Duration {
+	uni.txMu.Lock()
+	defer uni.txMu.Unlock()
+
+	wait := s.keepAlive - time.Since(uni.lastSent)
+	if wait > 0 {
+		return wait
+	}
+	if uni.last != nil {
+		s.sendData(uni, uni.last)
+	}
+	return s.keepAlive
+}
+
+// sendData sends a DataPacket on a universe with its settings and the next sequence number.
+// Level data is kept for keep-alive, packets with other Start Codes (eg: per-address priorities) are not repeated.
+// The caller shall hold the universe's txMu.
+func (s *Sender) sendData(uni *senderUniverse, d *packet.DataPacket) {
+	levels := d.GetStartCode() == packet.START_CODE_NULL
+	if uni.isPaused() { // keep the latest state to send on resume
+		if levels {
+			uni.last = d
 		}
+		return
 	}
+	f := s.frameData(uni, d)
+	uni.sequence += 1
+	f.Sequence = uni.sequence
+	s.sendPacket(uni, f)
+	if levels {
+		uni.last = d
+	}
+	uni.lastSent = time.Now()
+}
+
+// lastData returns the last level data sent on the universe, nil if none was sent yet.
+func (uni *senderUniverse) lastData() *packet.DataPacket {
+	uni.txMu.Lock()
+	defer uni.txMu.Unlock()
+	return uni.last
 }
 
 func (s *Sender) sendPacket(universe *senderUniverse, p packet.SACNPacket) {
+	buf := packetBuffers.Get().(*[packet.MAX_PACKET_SIZE]byte)
+	defer packetBuffers.Put(buf)
 
-	bytes, err := p.MarshalBinary()
+	n, err := p.MarshalTo(buf[:])
+	bytes := buf[:n]
 	if err != nil {
-		s.logger.Println("Error", err)
+		s.reportSend(universe.number, nil, p, err)
 		return
 	}
 
+	multicast, destinations := universe.addresses()
 	// send multicast if enabled
-	if universe.multicast {
-		_, err := s.conn.WriteToUDP(bytes, universeToAddress(universe.number))
-		if err != nil {
-			s.logger.Printf("Error sending multicast packet: %v\n", err)
-		}
+	if multicast {
+		addr := universeToAddress(universe.number)
+		_, err := s.conn.WriteToUDP(bytes, addr)
+		s.reportSend(universe.number, addr, p, err)
 	}
 	// send unicast
-	for _, dest := range universe.destinations {
+	for _, dest := range destinations {
 		_, err := s.conn.WriteToUDP(bytes, &dest)
-		if err != nil {
-			s.logger.Printf("Error sending unicast packet: %v\n", err)
-		}
+		s.reportSend(universe.number, &dest, p, err)
 	}
 }
 
+// GetCID returns the CID of the sender, used for all packets which do not set their own.
+func (s *Sender) GetCID() packet.CID {
+	return s.cid
+}
+
 // GetUniverses returns the list of all currently enabled universes for the sender.
 func (s *Sender) GetUniverses() []uint16 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	unis := make([]uint16, 0)
 	for n, uni := range s.universes {
-		if uni.enabled {
+		if uni.isEnabled() {
 			unis = append(unis, n)
 		}
 	}
 	return unis
 }
 
+// getUniverse returns a started universe. Stopped universes are not found, even while their termination packets are being sent.
+func (s *Sender) getUniverse(universe uint16) (*senderUniverse, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	uni, exists := s.universes[universe]
+	if !exists || uni.stopped.Load() {
+		return nil, false
+	}
+	return uni, true
+}
+
 // IsEnabled returns true if the universe is currently enabled.
 func (s *Sender) IsEnabled(universe uint16) bool {
-	uni, exists := s.universes[universe]
-	if exists && uni.enabled {
+	uni, exists := s.getUniverse(universe)
+	if exists && uni.isEnabled() {
 		return true
 	}
 	return false
 }
 
+func (uni *senderUniverse) isEnabled() bool {
+	uni.mu.Lock()
+	defer uni.mu.Unlock()
+	return uni.enabled
+}
+
+// addresses returns whether the universe is sent via multicast and its unicast destinations.
+func (uni *senderUniverse) addresses() (bool, []net.UDPAddr) {
+	uni.mu.Lock()
+	defer uni.mu.Unlock()
+	return uni.multicast, uni.destinations
+}
+
+// SetBackpressure sets the policy applied when packets are written to a universe's channel faster than they can be sent out,
+// and the number of packets which can be queued before it applies.
+func (s *Sender) SetBackpressure(universe uint16, policy BackpressurePolicy, queueSize int) error {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.queue.configure(queueSize, policy)
+		return nil
+	}
+	return universeNotFound(universe)
+}
+
+// GetDroppedPackets returns the number of packets of a universe discarded by its backpressure policy.
+// Packets coalesced by the maximum frame rate are not counted.
+func (s *Sender) GetDroppedPackets(universe uint16) (uint64, error) {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		return uni.queue.getDropped(), nil
+	}
+	return 0, universeNotFound(universe)
+}
+
+// GetMaxFrameRate returns the maximum number of DataPackets per second sent on the universe. 0 means unlimited.
+func (s *Sender) GetMaxFrameRate(universe uint16) (float64, error) {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		defer uni.mu.Unlock()
+		return uni.maxRate, nil
+	}
+	return 0, universeNotFound(universe)
+}
+
+// SetMaxFrameRate sets the maximum number of DataPackets per second sent on the universe (eg: [DMX_FRAME_RATE]). Use 0 for unlimited.
+// Packets written faster than this rate are coalesced: only the latest one received within a frame period is sent.
+// Writing to the channel returned by [Sender.StartUniverse] does not block when the limit is reached.
+func (s *Sender) SetMaxFrameRate(universe uint16, rate float64) error {
+	if rate < 0 {
+		return ErrInvalidFrameRate
+	}
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		defer uni.mu.Unlock()
+		uni.maxRate = rate
+		return nil
+	}
+	return universeNotFound(universe)
+}
+
 // IsMulticast returns wether or not multicast is turned on for the given universe.
 func (s *Sender) IsMulticast(universe uint16) (bool, error) {
-	uni, exists := s.universes[universe]
+	uni, exists := s.getUniverse(universe)
 	if exists {
-		return uni.multicast, nil
+		multicast, _ := uni.addresses()
+		return multicast, nil
 	}
-	return false, universeNotFoundError
+	return false, universeNotFound(universe)
 }
 
 // SetMulticast is for setting whether or not a universe should be send out via multicast.
 func (s *Sender) SetMulticast(universe uint16, multicast bool) error {
-	uni, exists := s.universes[universe]
+	uni, exists := s.getUniverse(universe)
 	if exists {
+		uni.mu.Lock()
 		uni.multicast = multicast
+		uni.mu.Unlock()
 		return nil
 	}
-	return universeNotFoundError
+	return universeNotFound(universe)
 }
 
 // GetDestinations returns the list of unicast destinations the universe is configured to send it's packets to.
 func (s *Sender) GetDestinations(universe uint16) ([]string, error) {
 	dests := make([]string, 0)
-	uni, exists := s.universes[universe]
+	uni, exists := s.getUniverse(universe)
 	if exists {
-		for _, dest := range uni.destinations {
+		_, destinations := uni.addresses()
+		for _, dest := range destinations {
 			dests = append(dests, dest.IP.String())
 		}
 		return dests, nil
 	}
-	return nil, universeNotFoundError
+	return nil, universeNotFound(universe)
 }
 
 // AddDestination adds a unicast destination that a universe should sent it's packets to.
 // destination should be in the form of a string (eg: "192.168.1.100").
 func (s *Sender) AddDestination(universe uint16, destination string) error {
 
-	addr, err := net.ResolveUDPAddr("udp", fmt.Sprintf("%s:%d", destination, SACN_PORT))
+	addr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(destination, fmt.Sprint(SACN_PORT)))
 	if err != nil {
 		return err
 	}
 
-	uni, exists := s.universes[universe]
+	uni, exists := s.getUniverse(universe)
 	if exists {
-		uni.destinations = append(uni.destinations, *addr)
+		uni.mu.Lock()
+		uni.destinations = append(slices.Clip(uni.destinations), *addr)
+		uni.mu.Unlock()
 		return nil
 	}
-	return universeNotFoundError
+	return universeNotFound(universe)
 }
 
 // SetDestinations sets the list of unicast destinations that a univese should sent it's packets to.
@@ -360,17 +813,37 @@ func (s *Sender) SetDestinations(universe uint16, destinations []string) error {
 
 	dests := make([]net.UDPAddr, 0)
 	for _, dest := range destinations {
-		addr, err := net.ResolveUDPAddr("udp", fmt.Sprintf("%s:%d", dest, SACN_PORT))
+		addr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(dest, fmt.Sprint(SACN_PORT)))
 		if err != nil {
 			return err
 		}
 		dests = append(dests, *addr)
 	}
 
-	uni, exists := s.universes[universe]
+	uni, exists := s.getUniverse(universe)
 	if exists {
+		uni.mu.Lock()
 		uni.destinations = dests
+		uni.mu.Unlock()
 		return nil
 	}
-	return universeNotFoundError
+	return universeNotFound(universe)
+}
+
+// Minimum duration between two DataPackets sent on the universe
+func (uni *senderUniverse) frameInterval() time.Duration {
+	uni.mu.Lock()
+	defer uni.mu.Unlock()
+	if uni.maxRate <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / uni.maxRate)
+}
+
+// Wakes up the send loop of the universe to apply changed settings
+func (uni *senderUniverse) wake() {
+	select {
+	case uni.notify <- struct{}{}:
+	default: // already notified
+	}
 }
@@ -0,0 +1,460 @@
+package sacn
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+// wireCapture receives the packets sent by a sender to the unicast destination 127.0.0.1
+type wireCapture struct {
+	conn *net.UDPConn
+}
+
+func newWireCapture(t *testing.T) *wireCapture {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: SACN_PORT})
+	if err != nil {
+		t.Fatalf("Could not listen for packets: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &wireCapture{conn: conn}
+}
+
+// next returns the next packet received within timeout, nil if none was received.
+func (c *wireCapture) next(t *testing.T, timeout time.Duration) packet.SACNPacket {
+	buf := make([]byte, packet.MAX_PACKET_SIZE)
+	c.conn.SetReadDeadline(time.Now().Add(timeout))
+	n, _, err := c.conn.ReadFromUDP(buf)
+	if err != nil {
+		return nil
+	}
+	p, err := packet.Unmarshal(buf[:n])
+	if err != nil {
+		t.Fatalf("Could not unmarshal packet: %v", err)
+	}
+	return p
+}
+
+// nextData returns the next DataPacket received within timeout, failing the test if none was received.
+func (c *wireCapture) nextData(t *testing.T, timeout time.Duration) *packet.DataPacket {
+	p := c.next(t, timeout)
+	d, ok := p.(*packet.DataPacket)
+	if !ok {
+		t.Fatalf("unexpected packet on the wire:\n- want: DataPacket\n-  got: %v", describePacket(p))
+	}
+	return d
+}
+
+func describePacket(p packet.SACNPacket) string {
+	switch p := p.(type) {
+	case nil:
+		return "nothing"
+	case *packet.DataPacket:
+		return fmt.Sprintf("DataPacket{sequence: %d, start code: 0x%x, data: %v, terminated: %v}", p.Sequence, p.GetStartCode(), p.GetData()[:max(p.Length, 1)-1], p.IsStreamTerminated())
+	case *packet.SyncPacket:
+		return fmt.Sprintf("SyncPacket{sequence: %d, sync address: %d}", p.Sequence, p.SyncAddress)
+	}
+	return fmt.Sprintf("%T", p)
+}
+
+// Starts a universe sending to the wire capture
+func startCapturedUniverse(t *testing.T, s *Sender, universe uint16) {
+	if _, err := s.StartUniverse(universe); err != nil {
+		t.Fatalf("Could not start universe: %v", err)
+	}
+	s.SetMulticast(universe, false)
+	s.AddDestination(universe, "127.0.0.1")
+}
+
+func TestSenderKeepAlive(t *testing.T) {
+	wire := newWireCapture(t)
+	s, err := NewSender("127.0.0.1", &SenderOptions{KeepAlive: 50 * time.Millisecond, DiscoveryInterval: -1})
+	if err != nil {
+		t.Fatalf("Could not create sender: %v", err)
+	}
+	defer s.Close()
+	startCapturedUniverse(t, s, 1)
+
+	p := packet.NewDataPacket()
+	p.SetData([]byte{1, 2, 3})
+	s.Send(1, p)
+
+	first := wire.nextData(t, time.Second)
+	sent := time.Now()
+	for i := 0; i < 3; i++ {
+		d := wire.nextData(t, time.Second)
+		if elapsed := time.Since(sent); elapsed < 40*time.Millisecond {
+			t.Fatalf("keep-alive sent too early: %v", elapsed)
+		}
+		sent = time.Now()
+		if want, got := first.Sequence+uint8(i+1), d.Sequence; want != got {
+			t.Fatalf("unexpected sequence of keep-alive %d:\n- want: %d\n-  got: %d", i, want, got)
+		}
+		if !bytes.Equal(first.GetData()[:3], d.GetData()[:3]) {
+			t.Fatalf("unexpected data of keep-alive %d:\n- want: %v\n-  got: %v", i, first.GetData()[:3], d.GetData()[:3])
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	s.StopUniverseAndWait(ctx, 1)
+	for {
+		d := wire.nextData(t, time.Second)
+		if d.IsStreamTerminated() {
+			break
+		}
+	}
+	wire.next(t, time.Second) // the remaining termination packets
+	wire.next(t, time.Second)
+	if p := wire.next(t, 150*time.Millisecond); p != nil {
+		t.Fatalf("unexpected packet after termination: %v", describePacket(p))
+	}
+}
+
+func TestSenderKeepAliveLevels(t *testing.T) {
+	wire := newWireCapture(t)
+	s, err := NewSender("127.0.0.1", &SenderOptions{KeepAlive: 50 * time.Millisecond, DiscoveryInterval: -1})
+	if err != nil {
+		t.Fatalf("Could not create sender: %v", err)
+	}
+	defer s.Close()
+	startCapturedUniverse(t, s, 1)
+
+	p := packet.NewDataPacket()
+	p.SetData([]byte{1, 2, 3})
+	s.Send(1, p)
+	priorities := packet.NewDataPacket()
+	priorities.SetStartCode(packet.START_CODE_PER_ADDRESS_PRIORITY)
+	priorities.SetData([]byte{100, 100, 100})
+	s.Send(1, priorities)
+	wire.nextData(t, time.Second)
+	wire.nextData(t, time.Second)
+
+	// Keep-alive repeats the level data, not the per-address priorities sent after it
+	for i := 0; i < 3; i++ {
+		d := wire.nextData(t, time.Second)
+		if d.GetStartCode() != packet.START_CODE_NULL || !bytes.Equal(d.GetData()[:3], []byte{1, 2, 3}) {
+			t.Fatalf("unexpected keep-alive %d:\n- want: level data %v\n-  got: %v", i, []byte{1, 2, 3}, describePacket(d))
+		}
+	}
+}
+
+func TestSenderFrameRate(t *testing.T) {
+	wire := newWireCapture(t)
+	s, err := NewSender("127.0.0.1", &SenderOptions{MaxFrameRate: 10, KeepAlive: 10 * time.Second, DiscoveryInterval: -1})
+	if err != nil {
+		t.Fatalf("Could not create sender: %v", err)
+	}
+	defer s.Close()
+	startCapturedUniverse(t, s, 1)
+
+	send := func(level byte) {
+		p := packet.NewDataPacket()
+		p.SetData([]byte{level})
+		s.Send(1, p)
+	}
+	expect := func(level byte, after time.Duration) {
+		sent := time.Now()
+		d := wire.nextData(t, time.Second)
+		if got := d.GetData()[0]; got != level {
+			t.Fatalf("unexpected level on the wire:\n- want: %d\n-  got: %d", level, got)
+		}
+		if elapsed := time.Since(sent); elapsed < after {
+			t.Fatalf("unexpected frame interval for level %d:\n- want: >= %v\n-  got: %v", level, after, elapsed)
+		}
+	}
+
+	// The first frame is sent right away, the newest of the following ones once the frame interval elapsed
+	send(1)
+	expect(1, 0)
+	for level := byte(2); level <= 5; level++ {
+		send(level)
+	}
+	expect(5, 80*time.Millisecond)
+	if p := wire.next(t, 200*time.Millisecond); p != nil {
+		t.Fatalf("unexpected packet after coalesced frames: %v", describePacket(p))
+	}
+
+	// A single frame held back by the limit is always sent eventually
+	send(6)
+	expect(6, 0)
+	send(7)
+	expect(7, 80*time.Millisecond)
+}
+
+func TestSenderPerAddressPriority(t *testing.T) {
+	wire := newWireCapture(t)
+	s, err := NewSender("127.0.0.1", &SenderOptions{KeepAlive: 10 * time.Second, DiscoveryInterval: -1})
+	if err != nil {
+		t.Fatalf("Could not create sender: %v", err)
+	}
+	defer s.Close()
+	s.priorityInterval = 50 * time.Millisecond
+	startCapturedUniverse(t, s, 1)
+
+	p := packet.NewDataPacket()
+	p.SetData([]byte{1, 2, 3})
+	s.Send(1, p)
+	levels := wire.nextData(t, time.Second)
+
+	// Priorities are sent right away then at every interval, sharing the sequence of the level data
+	s.SetPerAddressPriority(1, []byte{100, 0, 200})
+	sent := time.Now()
+	for i := 0; i < 4; i++ {
+		d := wire.nextData(t, time.Second)
+		if i > 0 {
+			if elapsed := time.Since(sent); elapsed < 40*time.Millisecond {
+				t.Fatalf("per-address priorities sent too early: %v", elapsed)
+			}
+		}
+		sent = time.Now()
+		if d.GetStartCode() != packet.START_CODE_PER_ADDRESS_PRIORITY || !bytes.Equal(d.GetData()[:3], []byte{100, 0, 200}) {
+			t.Fatalf("unexpected per-address priority packet %d: %v", i, describePacket(d))
+		}
+		if want, got := levels.Sequence+uint8(i+1), d.Sequence; want != got {
+			t.Fatalf("unexpected sequence of per-address priority packet %d:\n- want: %d\n-  got: %d", i, want, got)
+		}
+	}
+
+	// They stop together with the universe
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	s.StopUniverseAndWait(ctx, 1)
+	for {
+		d := wire.nextData(t, time.Second)
+		if d.IsStreamTerminated() {
+			if d.GetStartCode() != packet.START_CODE_NULL {
+				t.Fatalf("unexpected start code of termination packet:\n- want: 0x%x\n-  got: 0x%x", packet.START_CODE_NULL, d.GetStartCode())
+			}
+			break
+		}
+	}
+	wire.next(t, time.Second) // the remaining termination packets
+	wire.next(t, time.Second)
+	if p := wire.next(t, 150*time.Millisecond); p != nil {
+		t.Fatalf("unexpected packet after termination: %v", describePacket(p))
+	}
+}
+
+func TestSenderTerminationFlush(t *testing.T) {
+	wire := newWireCapture(t)
+	s, err := NewSender("127.0.0.1", &SenderOptions{MaxFrameRate: 2, KeepAlive: 10 * time.Second, DiscoveryInterval: -1})
+	if err != nil {
+		t.Fatalf("Could not create sender: %v", err)
+	}
+	startCapturedUniverse(t, s, 1)
+
+	for _, level := range []byte{1, 9} {
+		p := packet.NewDataPacket()
+		p.SetData([]byte{level, level})
+		s.Send(1, p)
+	}
+	s.Close()
+
+	// The frame held back by the frame rate limit is sent before the stream is terminated
+	expected := []struct {
+		levels     []byte
+		terminated bool
+	}{
+		{levels: []byte{1, 1}},
+		{levels: []byte{9, 9}},
+		{levels: []byte{9, 9}, terminated: true},
+		{levels: []byte{9, 9}, terminated: true},
+		{levels: []byte{9, 9}, terminated: true},
+	}
+	for i, e := range expected {
+		d := wire.nextData(t, time.Second)
+		if !bytes.Equal(d.GetData()[:2], e.levels) || d.IsStreamTerminated() != e.terminated {
+			t.Fatalf("unexpected packet %d on the wire:\n- want: %v (terminated: %v)\n-  got: %v", i, e.levels, e.terminated, describePacket(d))
+		}
+	}
+}
+
+// Run with -race: addresses and state of a universe are changed while it is sending and terminating
+func TestSenderConcurrentSettings(t *testing.T) {
+	s, err := NewSender("127.0.0.1", &SenderOptions{KeepAlive: time.Millisecond, DiscoveryInterval: -1})
+	if err != nil {
+		t.Fatalf("Could not create sender: %v", err)
+	}
+	defer s.Close()
+	s.StartUniverse(1)
+	s.Send(1, packet.NewDataPacket())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			s.SetMulticast(1, i%2 == 0)
+			s.AddDestination(1, "127.0.0.1")
+			s.SetDestinations(1, []string{"127.0.0.1"})
+			s.IsEnabled(1)
+			s.GetUniverses()
+			time.Sleep(100 * time.Microsecond)
+		}
+	}()
+	time.Sleep(2 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	s.StopUniverseAndWait(ctx, 1)
+	<-done
+}
+
+func TestSenderTermination(t *testing.T) {
+	priority := uint8(150)
+	s, err := NewSender("127.0.0.1", &SenderOptions{SourceName: "Sender", Priority: &priority})
+	if err != nil {
+		t.Fatalf("Could not create sender: %v", err)
+	}
+	ch, _ := s.StartUniverse(1)
+	s.StartUniverse(2)
+	uni, _ := s.getUniverse(1)
+
+	p := packet.NewDataPacket()
+	p.SetData([]byte{1, 2, 3})
+	ch <- p
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.StopUniverseAndWait(ctx, 1); err != nil {
+		t.Fatalf("unexpected error stopping universe: %v", err)
+	}
+	if s.IsEnabled(1) {
+		t.Fatalf("universe still enabled after termination")
+	}
+	var uniErr *UniverseError
+	if err := s.StopUniverse(1); !errors.As(err, &uniErr) || uniErr.Universe != 1 || !errors.Is(err, ErrUniverseNotFound) {
+		t.Fatalf("unexpected error stopping a terminated universe: %v", err)
+	}
+
+	// The last packet sent is the last of the 3 termination packets
+	last := s.frameData(uni, uni.last)
+	if !last.IsStreamTerminated() || last.Universe != 1 || last.CID != s.GetCID() || last.GetSourceName() != "Sender" || last.Priority != 150 {
+		t.Fatalf("unexpected termination packet: %+v", last)
+	}
+	if uni.sequence != 4 {
+		t.Fatalf("unexpected sequence after termination:\n- want: %d\n-  got: %d", 4, uni.sequence)
+	}
+
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("unexpected error on shutdown: %v", err)
+	}
+	if s.IsEnabled(2) {
+		t.Fatalf("universe still enabled after shutdown")
+	}
+	s.Close() // closing twice is allowed
+}
+
+func TestSenderTerminationLevels(t *testing.T) {
+	wire := newWireCapture(t)
+	priorities := packet.NewDataPacket()
+	priorities.SetStartCode(packet.START_CODE_PER_ADDRESS_PRIORITY)
+	priorities.SetData([]byte{100, 100})
+	levels := packet.NewDataPacket()
+	levels.SetData([]byte{1, 2, 3})
+
+	tests := []struct {
+		name     string
+		packets  []*packet.DataPacket
+		expected []byte
+	}{
+		{name: "After per-address priorities", packets: []*packet.DataPacket{levels, priorities}, expected: []byte{1, 2, 3}},
+		{name: "Without level data", packets: []*packet.DataPacket{priorities}, expected: []byte{}},
+		{name: "Nothing sent", expected: []byte{}},
+	}
+
+	for _, tt := range tests {
+		s, err := NewSender("127.0.0.1", &SenderOptions{KeepAlive: 10 * time.Second, DiscoveryInterval: -1})
+		if err != nil {
+			t.Fatalf("Could not create sender: %v", err)
+		}
+		startCapturedUniverse(t, s, 1)
+		for _, p := range tt.packets {
+			s.Send(1, p)
+		}
+		s.Close()
+
+		terminated := 0
+		for terminated < 3 {
+			d := wire.nextData(t, time.Second)
+			if !d.IsStreamTerminated() {
+				continue
+			}
+			terminated += 1
+			if d.GetStartCode() != packet.START_CODE_NULL || d.Length < 1 || !bytes.Equal(d.GetData()[:d.Length-1], tt.expected) {
+				t.Fatalf("unexpected termination packet on \"%s\":\n- want: start code 0x0, data: %v\n-  got: %v", tt.name, tt.expected, describePacket(d))
+			}
+		}
+	}
+}
+
+func TestSenderStoppedUniverse(t *testing.T) {
+	s, err := NewSender("127.0.0.1", &SenderOptions{DiscoveryInterval: -1})
+	if err != nil {
+		t.Fatalf("Could not create sender: %v", err)
+	}
+	defer s.Close()
+	s.StartUniverse(1)
+	s.Send(1, packet.NewDataPacket())
+	stopped, _ := s.getUniverse(1)
+	s.StopUniverse(1)
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "Send", call: func() error { return s.Send(1, packet.NewDataPacket()) }},
+		{name: "SetSlot", call: func() error { return s.SetSlot(1, 1, 255) }},
+		{name: "SetSlots", call: func() error { return s.SetSlots(1, 1, []byte{255}) }},
+		{name: "SetPriority", call: func() error { return s.SetPriority(1, 150) }},
+		{name: "StopUniverse", call: func() error { return s.StopUniverse(1) }},
+	}
+
+	for _, tt := range tests {
+		if err := tt.call(); !errors.Is(err, ErrUniverseNotFound) {
+			t.Fatalf("unexpected error on \"%s\":\n- want: %v\n-  got: %v", tt.name, ErrUniverseNotFound, err)
+		}
+	}
+
+	// The universe can be started again while the stopped one terminates, which does not remove it
+	if _, err := s.StartUniverse(1); err != nil {
+		t.Fatalf("Could not start universe again: %v", err)
+	}
+	select {
+	case <-stopped.done:
+	case <-time.After(time.Second):
+		t.Fatalf("stopped universe not terminated")
+	}
+	if !s.IsEnabled(1) {
+		t.Fatalf("universe started again was removed by the termination of the stopped one")
+	}
+	if err := s.Send(1, packet.NewDataPacket()); err != nil {
+		t.Fatalf("unexpected error sending on universe started again: %v", err)
+	}
+}
+
+func TestSenderSendWhileStopping(t *testing.T) {
+	s, err := NewSender("127.0.0.1", &SenderOptions{DiscoveryInterval: -1})
+	if err != nil {
+		t.Fatalf("Could not create sender: %v", err)
+	}
+	defer s.Close()
+
+	for i := 0; i < 20; i++ {
+		s.StartUniverse(1)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for s.Send(1, packet.NewDataPacket()) == nil {
+			}
+		}()
+		time.Sleep(time.Millisecond)
+		s.StopUniverse(1)
+		<-done
+	}
+}
@@ -0,0 +1,268 @@
+package sacn
+
+import (
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+// SetSourceName sets the source name of the sender, used in discovery packets and by all universes which do not have their own.
+// See [Sender.SetUniverseSourceName].
+func (s *Sender) SetSourceName(name string) error {
+	if len(name) > 64 {
+		return ErrSourceNameTooLong
+	}
+	s.mu.Lock()
+	s.sourceName = name
+	unis := make([]*senderUniverse, 0, len(s.universes))
+	for _, uni := range s.universes {
+		unis = append(unis, uni)
+	}
+	s.mu.Unlock()
+
+	for _, uni := range unis {
+		uni.settingsUpdated()
+	}
+	s.discoveryUni.wake() // advertise the new name
+	return nil
+}
+
+// GetSourceName returns the source name of the sender.
+func (s *Sender) GetSourceName() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sourceName
+}
+
+// SetUniverseSourceName sets the source name of the packets sent on a universe. Use an empty name to use the sender's source name.
+// A source name set in a [packet.DataPacket] takes precedence.
+func (s *Sender) SetUniverseSourceName(universe uint16, name string) error {
+	if len(name) > 64 {
+		return ErrSourceNameTooLong
+	}
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		uni.sourceName = name
+		uni.mu.Unlock()
+		uni.settingsUpdated()
+		return nil
+	}
+	return universeNotFound(universe)
+}
+
+// GetUniverseSourceName returns the source name of the packets sent on a universe.
+func (s *Sender) GetUniverseSourceName(universe uint16) (string, error) {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		name := uni.sourceName
+		uni.mu.Unlock()
+		if name == "" {
+			name = s.GetSourceName()
+		}
+		return name, nil
+	}
+	return "", universeNotFound(universe)
+}
+
+// SetPriority sets the priority (0 to 200) of the packets sent on a universe.
+// It applies to every packet of the universe: the Priority of a [packet.DataPacket] given to [Sender.Send] is ignored.
+// 0 is the lowest priority, receivers use the source with the highest priority.
+func (s *Sender) SetPriority(universe uint16, priority uint8) error {
+	if priority > 200 { // Section 6.2.3 of ANSI E1.31—2018
+		return ErrInvalidPriority
+	}
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		uni.priority = priority
+		uni.mu.Unlock()
+		uni.settingsUpdated()
+		return nil
+	}
+	return universeNotFound(universe)
+}
+
+// GetPriority returns the priority of the packets sent on a universe.
+func (s *Sender) GetPriority(universe uint16) (uint8, error) {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		defer uni.mu.Unlock()
+		return uni.priority, nil
+	}
+	return 0, universeNotFound(universe)
+}
+
+// SetPreview sets the Preview_Data option of the packets sent on a universe, meaning they are intended for visualisers and not for live output.
+// See Section 6.2.6 of ANSI E1.31—2018.
+func (s *Sender) SetPreview(universe uint16, preview bool) error {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		uni.preview = preview
+		uni.mu.Unlock()
+		uni.settingsUpdated()
+		return nil
+	}
+	return universeNotFound(universe)
+}
+
+// IsPreview returns true if the packets sent on a universe have the Preview_Data option set.
+func (s *Sender) IsPreview(universe uint16) (bool, error) {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		defer uni.mu.Unlock()
+		return uni.preview, nil
+	}
+	return false, universeNotFound(universe)
+}
+
+// SetSyncAddress sets the universe whose SyncPackets synchronise the data sent on a universe. Use 0 to disable synchronisation.
+// A sync address set in a [packet.DataPacket] takes precedence.
+func (s *Sender) SetSyncAddress(universe uint16, syncAddress uint16) error {
+	if syncAddress >= 64000 { // From ANSI E1.31-2019 Section 6.2.4
+		return ErrInvalidSyncAddress
+	}
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		uni.syncAddress = syncAddress
+		uni.mu.Unlock()
+		uni.settingsUpdated()
+		return nil
+	}
+	return universeNotFound(universe)
+}
+
+// GetSyncAddress returns the sync address of the packets sent on a universe, 0 if they are not synchronised.
+func (s *Sender) GetSyncAddress(universe uint16) (uint16, error) {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		defer uni.mu.Unlock()
+		return uni.syncAddress, nil
+	}
+	return 0, universeNotFound(universe)
+}
+
+// PauseUniverse stops sending packets on a universe without terminating its stream, keeping all its settings.
+// Packets written to the universe while it is paused update its state, the latest one is sent when it is resumed.
+// Receivers enter network data loss conditions if a universe stays paused longer than [NETWORK_DATA_LOSS_TIMEOUT].
+func (s *Sender) PauseUniverse(universe uint16) error {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		uni.paused = true
+		uni.mu.Unlock()
+		return nil
+	}
+	return universeNotFound(universe)
+}
+
+// ResumeUniverse resumes sending packets on a universe paused by [Sender.PauseUniverse], starting with its latest state.
+func (s *Sender) ResumeUniverse(universe uint16) error {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		uni.paused = false
+		uni.prioritiesChanged = uni.priorities != nil
+		uni.mu.Unlock()
+		uni.settingsUpdated()
+		return nil
+	}
+	return universeNotFound(universe)
+}
+
+// IsPaused returns true if the universe is paused.
+func (s *Sender) IsPaused(universe uint16) (bool, error) {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		return uni.isPaused(), nil
+	}
+	return false, universeNotFound(universe)
+}
+
+// SetBlackout sets all the levels sent on a universe to 0 while enabled, keeping its stream alive.
+// The state of the universe is kept and sent again when the blackout is disabled.
+// Per-address priorities are still sent during a blackout.
+func (s *Sender) SetBlackout(universe uint16, blackout bool) error {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		uni.blackout = blackout
+		uni.mu.Unlock()
+		uni.settingsUpdated()
+		return nil
+	}
+	return universeNotFound(universe)
+}
+
+// IsBlackout returns true if the levels of the universe are blacked out.
+func (s *Sender) IsBlackout(universe uint16) (bool, error) {
+	uni, exists := s.getUniverse(universe)
+	if exists {
+		uni.mu.Lock()
+		defer uni.mu.Unlock()
+		return uni.blackout, nil
+	}
+	return false, universeNotFound(universe)
+}
+
+// frameData returns a copy of a DataPacket with the current settings of the universe applied to the fields it does not set itself.
+// DataPackets are kept unmodified for keep-alive, so their next sending picks up changed settings.
+func (s *Sender) frameData(uni *senderUniverse, d *packet.DataPacket) *packet.DataPacket {
+	f := *d
+	if f.CID.IsZero() {
+		f.CID = s.cid
+	}
+
+	uni.mu.Lock()
+	name := uni.sourceName
+	priority := uni.priority
+	preview := uni.preview
+	syncAddress := uni.syncAddress
+	blackout := uni.blackout
+	uni.mu.Unlock()
+
+	if f.GetSourceName() == "" {
+		if name == "" {
+			name = s.GetSourceName()
+		}
+		f.SetSourceName(name)
+	}
+	f.Priority = priority // the universe priority always applies, see SetPriority
+	if preview {
+		f.SetPreviewData(true)
+	}
+	if f.SyncAddress == 0 {
+		f.SyncAddress = syncAddress
+	}
+	if blackout && f.GetStartCode() == packet.START_CODE_NULL {
+		clear(f.Data[1:])
+	}
+	return &f
+}
+
+func (uni *senderUniverse) isPaused() bool {
+	uni.mu.Lock()
+	defer uni.mu.Unlock()
+	return uni.paused
+}
+
+// Marks the settings of the universe as changed and wakes up its send loop to send them
+func (uni *senderUniverse) settingsUpdated() {
+	uni.mu.Lock()
+	uni.settingsChanged = true
+	uni.mu.Unlock()
+	uni.wake()
+}
+
+// Returns true once after the settings of the universe were changed
+func (uni *senderUniverse) takeSettingsChanged() bool {
+	uni.mu.Lock()
+	defer uni.mu.Unlock()
+	changed := uni.settingsChanged
+	uni.settingsChanged = false
+	return changed
+}
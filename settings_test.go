@@ -0,0 +1,156 @@
+package sacn
+
+import (
+	"errors"
+	"testing"
+
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+func TestSenderSettings(t *testing.T) {
+	s, err := NewSender("127.0.0.1", &SenderOptions{SourceName: "Sender"})
+	if err != nil {
+		t.Fatalf("Could not create sender: %v", err)
+	}
+	defer s.Close()
+	s.StartUniverse(1)
+	uni, _ := s.getUniverse(1)
+
+	type framing struct {
+		name        string
+		priority    uint8
+		preview     bool
+		syncAddress uint16
+	}
+	tests := []struct {
+		name     string
+		update   func()
+		packet   func(p *packet.DataPacket)
+		expected framing
+	}{
+		{
+			name:     "Sender defaults",
+			expected: framing{name: "Sender", priority: 100},
+		},
+		{
+			name: "Universe settings",
+			update: func() {
+				s.SetUniverseSourceName(1, "Universe")
+				s.SetPriority(1, 150)
+				s.SetPreview(1, true)
+				s.SetSyncAddress(1, 7)
+			},
+			expected: framing{name: "Universe", priority: 150, preview: true, syncAddress: 7},
+		},
+		{
+			name: "Packet takes precedence, except for the priority",
+			packet: func(p *packet.DataPacket) {
+				p.SetSourceName("Packet")
+				p.Priority = 50
+				p.SyncAddress = 8
+			},
+			expected: framing{name: "Packet", priority: 150, preview: true, syncAddress: 8},
+		},
+		{
+			name: "Sender source name",
+			update: func() {
+				s.SetUniverseSourceName(1, "")
+				s.SetSourceName("Renamed")
+			},
+			expected: framing{name: "Renamed", priority: 150, preview: true, syncAddress: 7},
+		},
+		{
+			name:     "Lowest priority",
+			update:   func() { s.SetPriority(1, 0) },
+			expected: framing{name: "Renamed", priority: 0, preview: true, syncAddress: 7},
+		},
+	}
+
+	for _, tt := range tests {
+		if tt.update != nil {
+			tt.update()
+		}
+		p := packet.NewDataPacket()
+		if tt.packet != nil {
+			tt.packet(p)
+		}
+		f := s.frameData(uni, p)
+		got := framing{name: f.GetSourceName(), priority: f.Priority, preview: f.IsPreviewData(), syncAddress: f.SyncAddress}
+		if got != tt.expected {
+			t.Fatalf("unexpected framing on \"%s\":\n- want: %+v\n-  got: %+v", tt.name, tt.expected, got)
+		}
+		if f.CID != s.GetCID() {
+			t.Fatalf("unexpected CID on \"%s\":\n- want: %v\n-  got: %v", tt.name, s.GetCID(), f.CID)
+		}
+	}
+
+	lowest := uint8(0)
+	background, err := NewSender("127.0.0.1", &SenderOptions{Priority: &lowest})
+	if err != nil {
+		t.Fatalf("Could not create sender: %v", err)
+	}
+	defer background.Close()
+	background.StartUniverse(1)
+	if priority, _ := background.GetPriority(1); priority != 0 {
+		t.Fatalf("unexpected priority of sender created with priority 0:\n- want: %d\n-  got: %d", 0, priority)
+	}
+
+	invalid := []struct {
+		name string
+		set  func() error
+		err  error
+	}{
+		{name: "Priority out of range", set: func() error { return s.SetPriority(1, 201) }, err: ErrInvalidPriority},
+		{name: "Negative frame rate", set: func() error { return s.SetMaxFrameRate(1, -1) }, err: ErrInvalidFrameRate},
+		{name: "Negative discovery interval", set: func() error { return s.SetDiscoveryInterval(-1) }, err: ErrInvalidDiscoveryInterval},
+		{name: "Negative fade duration", set: func() error { return s.Fade(1, 1, []byte{255}, -1, FadeLinear) }, err: ErrInvalidFadeDuration},
+		{name: "Source name too long", set: func() error { return s.SetSourceName(string(make([]byte, 65))) }, err: packet.ErrSourceNameTooLong},
+	}
+	for _, tt := range invalid {
+		if err := tt.set(); !errors.Is(err, tt.err) {
+			t.Fatalf("unexpected error on \"%s\":\n- want: %v\n-  got: %v", tt.name, tt.err, err)
+		}
+	}
+}
+
+func TestSenderPauseAndBlackout(t *testing.T) {
+	s, err := NewSender("127.0.0.1", &SenderOptions{})
+	if err != nil {
+		t.Fatalf("Could not create sender: %v", err)
+	}
+	defer s.Close()
+	s.StartUniverse(1)
+	uni, _ := s.getUniverse(1)
+
+	p := packet.NewDataPacket()
+	p.SetData([]byte{1, 2, 3})
+
+	s.PauseUniverse(1)
+	uni.txMu.Lock()
+	s.sendData(uni, p)
+	sequence, last := uni.sequence, uni.last
+	uni.txMu.Unlock()
+	if sequence != 0 || last != p {
+		t.Fatalf("unexpected state of paused universe:\n- want: sequence 0, last packet kept\n-  got: sequence %d, last packet kept %v", sequence, last == p)
+	}
+
+	s.SetBlackout(1, true)
+	f := s.frameData(uni, p)
+	if f.GetData()[0] != 0 || f.Length != p.Length {
+		t.Fatalf("unexpected data during blackout:\n- want: %v\n-  got: %v", []byte{0, 0, 0}, f.GetData())
+	}
+	if p.GetData()[0] != 1 {
+		t.Fatalf("blackout modified the state of the universe: %v", p.GetData())
+	}
+
+	s.SetBlackout(1, false)
+	if err := s.ResumeUniverse(1); err != nil {
+		t.Fatalf("unexpected error resuming universe: %v", err)
+	}
+	if paused, _ := s.IsPaused(1); paused {
+		t.Fatalf("universe still paused after resume")
+	}
+	if f := s.frameData(uni, p); f.GetData()[0] != 1 {
+		t.Fatalf("unexpected data after blackout: %v", f.GetData())
+	}
+}
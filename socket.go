@@ -0,0 +1,104 @@
+package sacn
+
+import (
+	"fmt"
+	"net"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// Socket tuning options of a [Sender] or [Receiver]. They apply to IPv4 and IPv6 sockets.
+// Options left to nil or 0 keep the defaults of the operating system.
+type SocketOptions struct {
+	MulticastTTL             *int // Number of router hops multicast packets can go through (hop limit in IPv6). Defaults to 1 on most systems.
+	DisableMulticastLoopback bool // Do not deliver multicast packets sent to receivers on the same host.
+	DSCP                     *int // Differentiated Services Code Point (0 to 63) marking packets for QoS on managed switches (eg: 46 for Expedited Forwarding).
+	SendBufferSize           int  // Size of the socket send buffer in bytes.
+	ReceiveBufferSize        int  // Size of the socket receive buffer in bytes.
+}
+
+func (o *SocketOptions) validate() error {
+	if o.MulticastTTL != nil && (*o.MulticastTTL < 0 || *o.MulticastTTL > 255) {
+		return ErrInvalidMulticastTTL
+	}
+	if o.DSCP != nil && (*o.DSCP < 0 || *o.DSCP > 63) {
+		return ErrInvalidDSCP
+	}
+	if o.SendBufferSize < 0 || o.ReceiveBufferSize < 0 {
+		return ErrInvalidBufferSize
+	}
+	return nil
+}
+
+// apply sets the options on a socket.
+// Sockets bound to an IPv6 address may also carry IPv4 traffic (dual-stack), the IPv4 options are then applied as well when supported.
+func (o *SocketOptions) apply(conn *net.UDPConn) error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+
+	if o.SendBufferSize > 0 {
+		if err := conn.SetWriteBuffer(o.SendBufferSize); err != nil {
+			return fmt.Errorf("Could not set send buffer size: %w", err)
+		}
+	}
+	if o.ReceiveBufferSize > 0 {
+		if err := conn.SetReadBuffer(o.ReceiveBufferSize); err != nil {
+			return fmt.Errorf("Could not set receive buffer size: %w", err)
+		}
+	}
+
+	isIPv4 := true
+	if addr, ok := conn.LocalAddr().(*net.UDPAddr); ok && addr.IP != nil && addr.IP.To4() == nil {
+		isIPv4 = false
+	}
+	if isIPv4 {
+		return o.applyIPv4(ipv4.NewPacketConn(conn))
+	}
+	if err := o.applyIPv6(ipv6.NewPacketConn(conn)); err != nil {
+		return err
+	}
+	if err := o.applyIPv4(ipv4.NewPacketConn(conn)); err != nil { // dual-stack, fails on IPv6-only sockets
+		return fmt.Errorf("IPv4 traffic of dual-stack socket: %w", err)
+	}
+	return nil
+}
+
+func (o *SocketOptions) applyIPv4(p *ipv4.PacketConn) error {
+	if o.MulticastTTL != nil {
+		if err := p.SetMulticastTTL(*o.MulticastTTL); err != nil {
+			return fmt.Errorf("Could not set multicast TTL: %w", err)
+		}
+	}
+	if o.DisableMulticastLoopback {
+		if err := p.SetMulticastLoopback(false); err != nil {
+			return fmt.Errorf("Could not disable multicast loopback: %w", err)
+		}
+	}
+	if o.DSCP != nil {
+		if err := p.SetTOS(*o.DSCP << 2); err != nil { // the 2 lowest bits are used by ECN
+			return fmt.Errorf("Could not set DSCP: %w", err)
+		}
+	}
+	return nil
+}
+
+func (o *SocketOptions) applyIPv6(p *ipv6.PacketConn) error {
+	if o.MulticastTTL != nil {
+		if err := p.SetMulticastHopLimit(*o.MulticastTTL); err != nil {
+			return fmt.Errorf("Could not set multicast hop limit: %w", err)
+		}
+	}
+	if o.DisableMulticastLoopback {
+		if err := p.SetMulticastLoopback(false); err != nil {
+			return fmt.Errorf("Could not disable multicast loopback: %w", err)
+		}
+	}
+	if o.DSCP != nil {
+		if err := p.SetTrafficClass(*o.DSCP << 2); err != nil {
+			return fmt.Errorf("Could not set DSCP: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package sacn
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+func TestSocketOptions(t *testing.T) {
+	ttl, dscp := 8, 46
+	options := SocketOptions{MulticastTTL: &ttl, DisableMulticastLoopback: true, DSCP: &dscp, SendBufferSize: 1 << 16}
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("Could not create socket: %v", err)
+	}
+	defer conn.Close()
+	if err := options.apply(conn); err != nil {
+		t.Fatalf("unexpected error applying options: %v", err)
+	}
+	p := ipv4.NewPacketConn(conn)
+	if ttl, _ := p.MulticastTTL(); ttl != 8 {
+		t.Fatalf("unexpected multicast TTL:\n- want: %d\n-  got: %d", 8, ttl)
+	}
+	if loopback, _ := p.MulticastLoopback(); loopback {
+		t.Fatalf("multicast loopback not disabled")
+	}
+	if tos, _ := p.TOS(); tos != 46<<2 {
+		t.Fatalf("unexpected TOS:\n- want: 0x%x\n-  got: 0x%x", 46<<2, tos)
+	}
+
+	conn6, err := net.ListenUDP("udp6", &net.UDPAddr{IP: net.IPv6loopback})
+	if err != nil {
+		t.Logf("Skipping IPv6: %v", err)
+	} else {
+		defer conn6.Close()
+		if err := options.apply(conn6); err != nil {
+			t.Fatalf("unexpected error applying options on IPv6: %v", err)
+		}
+		p6 := ipv6.NewPacketConn(conn6)
+		if hops, _ := p6.MulticastHopLimit(); hops != 8 {
+			t.Fatalf("unexpected multicast hop limit:\n- want: %d\n-  got: %d", 8, hops)
+		}
+		if class, _ := p6.TrafficClass(); class != 46<<2 {
+			t.Fatalf("unexpected traffic class:\n- want: 0x%x\n-  got: 0x%x", 46<<2, class)
+		}
+	}
+
+	// Options can explicitly be set to 0
+	zero := 0
+	if err := (&SocketOptions{MulticastTTL: &zero, DSCP: &zero}).apply(conn); err != nil {
+		t.Fatalf("unexpected error applying options set to 0: %v", err)
+	}
+	if ttl, _ := p.MulticastTTL(); ttl != 0 {
+		t.Fatalf("unexpected multicast TTL:\n- want: %d\n-  got: %d", 0, ttl)
+	}
+	if tos, _ := p.TOS(); tos != 0 {
+		t.Fatalf("unexpected TOS:\n- want: 0x%x\n-  got: 0x%x", 0, tos)
+	}
+
+	// IPv4 options also apply to the IPv4 traffic of dual-stack sockets
+	dual, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv6unspecified})
+	if err != nil {
+		t.Logf("Skipping dual-stack: %v", err)
+	} else {
+		defer dual.Close()
+		if err := options.apply(dual); err != nil {
+			t.Fatalf("unexpected error applying options on dual-stack socket: %v", err)
+		}
+		if ttl, _ := ipv4.NewPacketConn(dual).MulticastTTL(); ttl != 8 {
+			t.Fatalf("unexpected multicast TTL of dual-stack socket:\n- want: %d\n-  got: %d", 8, ttl)
+		}
+	}
+
+	invalidTTL, invalidDSCP := 256, 64
+	invalid := []struct {
+		options SocketOptions
+		err     error
+	}{
+		{options: SocketOptions{MulticastTTL: &invalidTTL}, err: ErrInvalidMulticastTTL},
+		{options: SocketOptions{DSCP: &invalidDSCP}, err: ErrInvalidDSCP},
+		{options: SocketOptions{ReceiveBufferSize: -1}, err: ErrInvalidBufferSize},
+	}
+	for _, tt := range invalid {
+		if err := tt.options.validate(); !errors.Is(err, tt.err) {
+			t.Fatalf("unexpected error on invalid options %+v:\n- want: %v\n-  got: %v", tt.options, tt.err, err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package sacn
+
+import (
+	"slices"
+)
+
+// NewSource creates a new logical source sending on the socket of the sender, with its own CID, source name, priority,
+// universes and universe discovery. It is used like any other [Sender], options are the same as [NewSender].
+//
+// Sources are used to emulate several independent sources in one process, such as the layers of a media server.
+// Their CID shall be different from the CIDs of the sender and its other sources.
+// Closing a source terminates its universes only. Closing the sender closes all its sources.
+func (s *Sender) NewSource(options *SenderOptions) (*Sender, error) {
+	if s.parent != nil {
+		return s.parent.NewSource(options)
+	}
+
+	opts := *options // options of the sender are shared, only copy the ones of the source
+	if opts.Logger == nil {
+		opts.Logger = s.logger
+	}
+	err := opts.setDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	s.sourcesMu.Lock()
+	defer s.sourcesMu.Unlock()
+	if s.closing {
+		return nil, ErrSenderClosed
+	}
+	if opts.CID == s.cid {
+		return nil, ErrCIDInUse
+	}
+	for _, source := range s.sources {
+		if opts.CID == source.cid {
+			return nil, ErrCIDInUse
+		}
+	}
+
+	source := newSender(s.conn, &opts)
+	source.parent = s
+	s.sources = append(s.sources, source)
+	return source, nil
+}
+
+// GetSources returns the sources created with [Sender.NewSource] which are not closed.
+func (s *Sender) GetSources() []*Sender {
+	s.sourcesMu.Lock()
+	defer s.sourcesMu.Unlock()
+	return slices.Clone(s.sources)
+}
+
+func (s *Sender) removeSource(source *Sender) {
+	s.sourcesMu.Lock()
+	defer s.sourcesMu.Unlock()
+	s.sources = slices.DeleteFunc(s.sources, func(other *Sender) bool { return other == source })
+}
@@ -0,0 +1,64 @@
+package sacn
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSenderSources(t *testing.T) {
+	s, err := NewSender("127.0.0.1", &SenderOptions{SourceName: "Main"})
+	if err != nil {
+		t.Fatalf("Could not create sender: %v", err)
+	}
+
+	priority := uint8(150)
+	effects, err := s.NewSource(&SenderOptions{SourceName: "Effects", Priority: &priority})
+	if err != nil {
+		t.Fatalf("Could not create source: %v", err)
+	}
+	preview, err := effects.NewSource(&SenderOptions{SourceName: "Preview", CID: NewNameCID("preview")})
+	if err != nil {
+		t.Fatalf("Could not create source: %v", err)
+	}
+	if _, err := s.NewSource(&SenderOptions{CID: preview.GetCID()}); !errors.Is(err, ErrCIDInUse) {
+		t.Fatalf("No error returned on source with a duplicated CID")
+	}
+	if sources := s.GetSources(); len(sources) != 2 {
+		t.Fatalf("unexpected number of sources:\n- want: %d\n-  got: %d", 2, len(sources))
+	}
+	if effects.GetCID() == s.GetCID() || effects.GetSourceName() != "Effects" {
+		t.Fatalf("unexpected source identity: %v %q", effects.GetCID(), effects.GetSourceName())
+	}
+
+	// Sources have their own universes
+	s.StartUniverse(1)
+	effects.StartUniverse(1)
+	preview.StartUniverse(2)
+	if priority, _ := effects.GetPriority(1); priority != 150 {
+		t.Fatalf("unexpected priority of source universe:\n- want: %d\n-  got: %d", 150, priority)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := effects.Shutdown(ctx); err != nil {
+		t.Fatalf("unexpected error closing source: %v", err)
+	}
+	if !s.IsEnabled(1) || effects.IsEnabled(1) {
+		t.Fatalf("unexpected universes after closing a source")
+	}
+	if sources := s.GetSources(); len(sources) != 1 || sources[0] != preview {
+		t.Fatalf("unexpected sources after closing a source: %v", sources)
+	}
+
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("unexpected error closing sender: %v", err)
+	}
+	if preview.IsEnabled(2) {
+		t.Fatalf("source universe still enabled after closing the sender")
+	}
+	if _, err := s.NewSource(&SenderOptions{}); !errors.Is(err, ErrSenderClosed) {
+		t.Fatalf("No error returned on new source of a closed sender")
+	}
+}
@@ -0,0 +1,145 @@
+package sacn
+
+import (
+	"net"
+	"time"
+
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+// Type of a [SourceEvent]
+type SourceEventType int
+
+// Possible types of [SourceEvent].
+const (
+	SourceAppeared   SourceEventType = iota // A new source (CID) started sending data on a universe.
+	SourceChanged                           // A source changed its priority or source name.
+	SourceTerminated                        // A source sent a packet with the Stream_Terminated bit set.
+	SourceTimedOut                          // A source did not send any data for [NETWORK_DATA_LOSS_TIMEOUT].
+)
+
+func (t SourceEventType) String() string {
+	switch t {
+	case SourceAppeared:
+		return "appeared"
+	case SourceChanged:
+		return "changed"
+	case SourceTerminated:
+		return "terminated"
+	case SourceTimedOut:
+		return "timed out"
+	}
+	return "unknown"
+}
+
+// SourceEvent describes a change in the lifecycle of a source sending data on a universe.
+type SourceEvent struct {
+	Type       SourceEventType
+	Universe   uint16      // The universe the source is sending on.
+	CID        packet.CID  // The CID of the source.
+	SourceName string      // The latest source name of the source.
+	Source     net.UDPAddr // The latest address the source sent from.
+	Priority   uint8       // The latest priority of the source on the universe.
+}
+
+// SourceEventCallbackFunc is the function type to be used with [Receiver.RegisterSourceEventCallback].
+type SourceEventCallbackFunc func(event SourceEvent)
+
+type trackedSource struct {
+	name     string
+	addr     net.UDPAddr
+	priority uint8
+	lastSeen time.Time
+}
+
+// sourceTracker follows every source (CID) sending data on each universe.
+type sourceTracker struct {
+	sources map[uint16]map[packet.CID]*trackedSource
+}
+
+func newSourceTracker() *sourceTracker {
+	return &sourceTracker{
+		sources: make(map[uint16]map[packet.CID]*trackedSource),
+	}
+}
+
+// update records a received DataPacket and returns the resulting event, if any.
+func (t *sourceTracker) update(d *packet.DataPacket, addr net.UDPAddr, now time.Time) (SourceEvent, bool) {
+	sources, exists := t.sources[d.Universe]
+	if !exists {
+		sources = make(map[packet.CID]*trackedSource)
+		t.sources[d.Universe] = sources
+	}
+
+	src, known := sources[d.CID]
+	if d.IsStreamTerminated() {
+		if !known {
+			return SourceEvent{}, false
+		}
+		delete(sources, d.CID)
+		src.addr = addr
+		return src.event(SourceTerminated, d.Universe, d.CID), true
+	}
+
+	if !known {
+		src = &trackedSource{
+			name:     d.GetSourceName(),
+			addr:     addr,
+			priority: d.Priority,
+			lastSeen: now,
+		}
+		sources[d.CID] = src
+		return src.event(SourceAppeared, d.Universe, d.CID), true
+	}
+
+	src.addr = addr
+	src.lastSeen = now
+	if d.GetStartCode() != 0 { // Only level data carries the source's priority and name (eg: not 0xDD per-address priority)
+		return SourceEvent{}, false
+	}
+	name := d.GetSourceName()
+	if name != src.name || d.Priority != src.priority {
+		src.name = name
+		src.priority = d.Priority
+		return src.event(SourceChanged, d.Universe, d.CID), true
+	}
+	return SourceEvent{}, false
+}
+
+// isActive returns true if the source is sending data on any universe.
+func (t *sourceTracker) isActive(cid packet.CID) bool {
+	for _, sources := range t.sources {
+		if _, exists := sources[cid]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// expire removes sources which have not sent data for the network data loss timeout and returns their events.
+func (t *sourceTracker) expire(now time.Time) []SourceEvent {
+	var events []SourceEvent
+	for universe, sources := range t.sources {
+		for cid, src := range sources {
+			if now.Sub(src.lastSeen) > time.Millisecond*NETWORK_DATA_LOSS_TIMEOUT {
+				events = append(events, src.event(SourceTimedOut, universe, cid))
+				delete(sources, cid)
+			}
+		}
+		if len(sources) == 0 {
+			delete(t.sources, universe)
+		}
+	}
+	return events
+}
+
+func (s *trackedSource) event(eventType SourceEventType, universe uint16, cid packet.CID) SourceEvent {
+	return SourceEvent{
+		Type:       eventType,
+		Universe:   universe,
+		CID:        cid,
+		SourceName: s.name,
+		Source:     s.addr,
+		Priority:   s.priority,
+	}
+}
@@ -0,0 +1,103 @@
+package sacn
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+func TestSourceTracker(t *testing.T) {
+	tracker := newSourceTracker()
+	addr := net.UDPAddr{IP: net.IPv4(192, 168, 1, 10), Port: SACN_PORT}
+	now := time.Now()
+
+	p := packet.NewDataPacket()
+	p.CID = packet.CID{0x01}
+	p.Universe = 1
+	p.SetSourceName("Console")
+
+	tests := []struct {
+		name     string
+		update   func()
+		at       time.Duration
+		expected []SourceEventType
+	}{
+		{
+			name:     "First packet",
+			update:   func() {},
+			expected: []SourceEventType{SourceAppeared},
+		},
+		{
+			name:     "Same packet",
+			update:   func() {},
+			at:       100 * time.Millisecond,
+			expected: nil,
+		},
+		{
+			name:     "Priority change",
+			update:   func() { p.Priority = 150 },
+			at:       200 * time.Millisecond,
+			expected: []SourceEventType{SourceChanged},
+		},
+		{
+			name:     "Per-address priority packet",
+			update:   func() { p.SetStartCode(0xDD); p.Priority = 100 },
+			at:       300 * time.Millisecond,
+			expected: nil,
+		},
+		{
+			name:     "Source name change",
+			update:   func() { p.SetStartCode(0); p.Priority = 150; p.SetSourceName("Backup") },
+			at:       400 * time.Millisecond,
+			expected: []SourceEventType{SourceChanged},
+		},
+		{
+			name:     "Stream terminated",
+			update:   func() { p.SetStreamTerminated(true) },
+			at:       500 * time.Millisecond,
+			expected: []SourceEventType{SourceTerminated},
+		},
+		{
+			name:     "Terminated again",
+			update:   func() {},
+			at:       600 * time.Millisecond,
+			expected: nil,
+		},
+		{
+			name:     "Back",
+			update:   func() { p.Options = 0 },
+			at:       700 * time.Millisecond,
+			expected: []SourceEventType{SourceAppeared},
+		},
+	}
+
+	for _, tt := range tests {
+		tt.update()
+		var events []SourceEventType
+		for _, event := range tracker.expire(now.Add(tt.at)) {
+			events = append(events, event.Type)
+		}
+		if event, ok := tracker.update(p, addr, now.Add(tt.at)); ok {
+			if event.CID != p.CID || event.Universe != 1 || event.SourceName != p.GetSourceName() || !event.Source.IP.Equal(addr.IP) {
+				t.Fatalf("Unexpected event content on \"%s\": %+v", tt.name, event)
+			}
+			events = append(events, event.Type)
+		}
+
+		if len(events) != len(tt.expected) {
+			t.Fatalf("Unexpected events on \"%s\": %v != %v", tt.name, events, tt.expected)
+		}
+		for i := range events {
+			if events[i] != tt.expected[i] {
+				t.Fatalf("Unexpected events on \"%s\": %v != %v", tt.name, events, tt.expected)
+			}
+		}
+	}
+
+	events := tracker.expire(now.Add(700*time.Millisecond + time.Millisecond*NETWORK_DATA_LOSS_TIMEOUT + 1))
+	if len(events) != 1 || events[0].Type != SourceTimedOut {
+		t.Fatalf("Unexpected events on timeout: %v", events)
+	}
+}
@@ -0,0 +1,127 @@
+package sacn
+
+import (
+	"sync"
+
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+// UniverseStats are the reception statistics of a universe returned by [Receiver.GetUniverseStats].
+//
+// Lost packets are detected from gaps in the sequence numbers of each source.
+// They are counted as dropped locally when the receive queue of the socket overflowed while they were missing (only detected on Linux),
+// else as lost on the wire (network fault, congestion on the way, or a source skipping sequence numbers).
+type UniverseStats struct {
+	Received       uint64 // DataPackets received on the universe.
+	OutOfOrder     uint64 // DataPackets received with an older sequence number than the previous one of their source.
+	Lost           uint64 // DataPackets missing in the sequence numbers of the sources, LostOnWire + DroppedLocally.
+	LostOnWire     uint64 // Lost DataPackets which never reached the host.
+	DroppedLocally uint64 // Lost DataPackets dropped by the receive queue of the socket, because packets were not read fast enough.
+}
+
+// GetUniverseStats returns the reception statistics of a universe, false if nothing was received on it.
+func (r *Receiver) GetUniverseStats(universe uint16) (UniverseStats, bool) {
+	return r.stats.get(universe)
+}
+
+// GetSocketOverflows returns the number of packets dropped by the receive queue of the socket since the receiver was started,
+// on all universes. It is always 0 where not supported (only Linux). See [SocketOptions] to increase the size of the queue.
+func (r *Receiver) GetSocketOverflows() uint64 {
+	r.stats.mu.Lock()
+	defer r.stats.mu.Unlock()
+	return r.stats.overflows
+}
+
+// lossTracker follows the sequence numbers of each source to detect lost packets, and attributes them to socket overflows.
+type lossTracker struct {
+	mu           sync.Mutex
+	universes    map[uint16]*universeLoss
+	lastCounter  uint32 // last socket overflow counter received from the kernel
+	overflows    uint64 // total socket overflows
+	unattributed uint64 // socket overflows not attributed to a sequence gap yet
+}
+
+type universeLoss struct {
+	stats   UniverseStats
+	sources map[packet.CID]*sequenceState
+}
+
+type sequenceState struct {
+	sequence  uint8
+	overflows uint64 // total socket overflows when the last packet of the source was received
+}
+
+func newLossTracker() *lossTracker {
+	return &lossTracker{
+		universes: make(map[uint16]*universeLoss),
+	}
+}
+
+// setOverflowCounter records the cumulative socket overflow counter received with a packet.
+func (t *lossTracker) setOverflowCounter(counter uint32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delta := uint64(counter - t.lastCounter) // the counter wraps around
+	t.lastCounter = counter
+	t.overflows += delta
+	t.unattributed += delta
+}
+
+// update records a received DataPacket.
+func (t *lossTracker) update(d *packet.DataPacket) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	uni, exists := t.universes[d.Universe]
+	if !exists {
+		uni = &universeLoss{sources: make(map[packet.CID]*sequenceState)}
+		t.universes[d.Universe] = uni
+	}
+	uni.stats.Received += 1
+
+	src, known := uni.sources[d.CID]
+	if d.IsStreamTerminated() {
+		delete(uni.sources, d.CID)
+		return
+	}
+	if !known {
+		uni.sources[d.CID] = &sequenceState{sequence: d.Sequence, overflows: t.overflows}
+		return
+	}
+
+	diff := int8(d.Sequence - src.sequence)
+	switch {
+	case diff > 0:
+		gap := uint64(diff - 1)
+		local := min(gap, t.overflows-src.overflows, t.unattributed) // only overflows which happened while the packets were missing
+		t.unattributed -= local
+		uni.stats.Lost += gap
+		uni.stats.DroppedLocally += local
+		uni.stats.LostOnWire += gap - local
+	case diff > -20: // Section 6.7.2 of ANSI E1.31—2018
+		uni.stats.OutOfOrder += 1
+		return
+	default: // the source restarted its sequence
+	}
+	src.sequence = d.Sequence
+	src.overflows = t.overflows
+}
+
+// forget stops following the sequence of a source which timed out on a universe.
+func (t *lossTracker) forget(universe uint16, cid packet.CID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if uni, exists := t.universes[universe]; exists {
+		delete(uni.sources, cid)
+	}
+}
+
+func (t *lossTracker) get(universe uint16) (UniverseStats, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	uni, exists := t.universes[universe]
+	if !exists {
+		return UniverseStats{}, false
+	}
+	return uni.stats, true
+}
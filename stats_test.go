@@ -0,0 +1,111 @@
+package sacn
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+func TestLossTracker(t *testing.T) {
+	tracker := newLossTracker()
+	p := packet.NewDataPacket()
+	p.Universe = 1
+	p.CID = packet.CID{0x01}
+
+	tests := []struct {
+		name     string
+		sequence uint8
+		counter  uint32 // socket overflow counter received with the packet
+		expected UniverseStats
+	}{
+		{
+			name:     "First packet",
+			sequence: 10,
+			expected: UniverseStats{Received: 1},
+		},
+		{
+			name:     "Next packet",
+			sequence: 11,
+			expected: UniverseStats{Received: 2},
+		},
+		{
+			name:     "Lost on the wire",
+			sequence: 14,
+			expected: UniverseStats{Received: 3, Lost: 2, LostOnWire: 2},
+		},
+		{
+			name:     "Dropped locally",
+			sequence: 17,
+			counter:  2,
+			expected: UniverseStats{Received: 4, Lost: 4, LostOnWire: 2, DroppedLocally: 2},
+		},
+		{
+			name:     "More gaps than overflows",
+			sequence: 21,
+			counter:  3,
+			expected: UniverseStats{Received: 5, Lost: 7, LostOnWire: 4, DroppedLocally: 3},
+		},
+		{
+			name:     "Out of order",
+			sequence: 19,
+			counter:  3,
+			expected: UniverseStats{Received: 6, Lost: 7, LostOnWire: 4, DroppedLocally: 3, OutOfOrder: 1},
+		},
+		{
+			name:     "Source restarted",
+			sequence: 254,
+			counter:  3,
+			expected: UniverseStats{Received: 7, Lost: 7, LostOnWire: 4, DroppedLocally: 3, OutOfOrder: 1},
+		},
+		{
+			name:     "Sequence wraps around",
+			sequence: 1,
+			counter:  3,
+			expected: UniverseStats{Received: 8, Lost: 9, LostOnWire: 6, DroppedLocally: 3, OutOfOrder: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		tracker.setOverflowCounter(tt.counter)
+		p.Sequence = tt.sequence
+		tracker.update(p)
+
+		stats, _ := tracker.get(1)
+		if stats != tt.expected {
+			t.Fatalf("unexpected stats on \"%s\":\n- want: %+v\n-  got: %+v", tt.name, tt.expected, stats)
+		}
+	}
+
+	if _, exists := tracker.get(2); exists {
+		t.Fatalf("unexpected stats for a universe without packets")
+	}
+}
+
+func TestLossTrackerSourceTimeout(t *testing.T) {
+	r := newTestReceiver(t)
+
+	info := PacketInfo{Source: net.UDPAddr{IP: net.ParseIP("10.0.0.1")}}
+	for i := 0; i < 10; i++ {
+		p := packet.NewDataPacket()
+		p.CID = packet.CID{0x01, byte(i)}
+		p.Universe = 1
+		r.handlePacket(p, info)
+	}
+	if sources := len(r.stats.universes[1].sources); sources != 10 {
+		t.Fatalf("unexpected number of followed sources:\n- want: %d\n-  got: %d", 10, sources)
+	}
+
+	// Sources are forgotten once they time out, without sending Stream_Terminated
+	for _, src := range r.sources.sources[1] {
+		src.lastSeen = time.Now().Add(-2 * NETWORK_DATA_LOSS_TIMEOUT * time.Millisecond)
+	}
+	r.checkTimeouts()
+	if sources := len(r.stats.universes[1].sources); sources != 0 {
+		t.Fatalf("unexpected number of followed sources after timeout:\n- want: %d\n-  got: %d", 0, sources)
+	}
+	if stats, _ := r.GetUniverseStats(1); stats.Received != 10 {
+		t.Fatalf("unexpected stats kept after timeout:\n- want: %d received\n-  got: %+v", 10, stats)
+	}
+}
@@ -0,0 +1,171 @@
+package sacn
+
+import (
+	"net"
+	"sort"
+	"sync"
+
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+// A group of universes synchronised by a sync universe. See Section 11 of ANSI E1.31—2018.
+type syncGroup struct {
+	universe uint16
+	members  map[uint16]bool
+
+	mu sync.Mutex // serialises frames sent on the group
+}
+
+// StartSyncGroup defines a group of universes synchronised with SyncPackets sent on the sync universe.
+// Use [Sender.SendSyncFrame] to send data on several members at once followed by a single [packet.SyncPacket].
+// Member universes can be started before or after the group is created.
+func (s *Sender) StartSyncGroup(syncUniverse uint16, members []uint16) error {
+	if syncUniverse < 1 || syncUniverse >= 64000 { // From ANSI E1.31-2019 Section 6.2.7
+		return &UniverseError{Universe: syncUniverse, Err: ErrInvalidUniverse}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.syncGroups[syncUniverse]; exists {
+		return &UniverseError{Universe: syncUniverse, Err: ErrSyncGroupAlreadyExists}
+	}
+	group := &syncGroup{
+		universe: syncUniverse,
+		members:  make(map[uint16]bool),
+	}
+	for _, member := range members {
+		group.members[member] = true
+	}
+	s.syncGroups[syncUniverse] = group
+	return nil
+}
+
+// StopSyncGroup removes a sync group. The member universes keep sending.
+func (s *Sender) StopSyncGroup(syncUniverse uint16) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.syncGroups[syncUniverse]; !exists {
+		return &UniverseError{Universe: syncUniverse, Err: ErrSyncGroupNotFound}
+	}
+	delete(s.syncGroups, syncUniverse)
+	return nil
+}
+
+// GetSyncGroupMembers returns the list of universes synchronised by the sync universe.
+func (s *Sender) GetSyncGroupMembers(syncUniverse uint16) ([]uint16, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	group, exists := s.syncGroups[syncUniverse]
+	if !exists {
+		return nil, &UniverseError{Universe: syncUniverse, Err: ErrSyncGroupNotFound}
+	}
+	members := make([]uint16, 0, len(group.members))
+	for member := range group.members {
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// SendSyncFrame sends a frame covering several universes of a sync group.
+// The frame maps member universes to the [packet.DataPacket] to send on them.
+//
+// All the DataPackets are sent first, with their SyncAddress set to the sync universe and the next sequence number of their universe.
+// Exactly one [packet.SyncPacket] is then sent, with the sync group's own sequence number.
+// Packets are sent immediately: they replace any packet held back by the frame rate limit of their universe.
+//
+// Paused members (see [Sender.PauseUniverse]) keep their DataPacket as their latest state, to be sent unsynchronised when they are resumed,
+// and the SyncPacket is only addressed to the members which sent data. If all the members in the frame are paused, no SyncPacket is sent.
+// SyncPackets share their sequence with the ones written to the sync universe, if it is started as well.
+func (s *Sender) SendSyncFrame(syncUniverse uint16, frame map[uint16]*packet.DataPacket) error {
+	s.mu.RLock()
+	group, exists := s.syncGroups[syncUniverse]
+	universes := make([]*senderUniverse, 0, len(frame))
+	var err error
+	if exists {
+		for universe := range frame {
+			uni, started := s.universes[universe]
+			if !group.members[universe] {
+				err = &UniverseError{Universe: universe, Err: ErrNotSyncGroupMember}
+				break
+			}
+			if !started || uni.stopped.Load() || !uni.isEnabled() {
+				err = universeNotFound(universe)
+				break
+			}
+			if frame[universe] == nil {
+				err = &UniverseError{Universe: universe, Err: ErrMissingPacket}
+				break
+			}
+			universes = append(universes, uni)
+		}
+	}
+	s.mu.RUnlock()
+	if !exists {
+		return &UniverseError{Universe: syncUniverse, Err: ErrSyncGroupNotFound}
+	}
+	if err != nil {
+		return err
+	}
+	sort.Slice(universes, func(i, j int) bool { return universes[i].number < universes[j].number })
+
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	// The SyncPacket is sent to the sync universe's multicast address and to the unicast destinations of all members in the frame
+	syncUni := &senderUniverse{
+		number:       syncUniverse,
+		destinations: make([]net.UDPAddr, 0),
+	}
+	seen := make(map[string]bool)
+	sent := false
+
+	for _, uni := range universes {
+		d := uni.acceptData(frame[uni.number])
+		paused := uni.isPaused()
+		d.SyncAddress = syncUniverse
+		if paused { // sent on resume, without a SyncPacket to apply it
+			d.SyncAddress = 0
+		}
+
+		s.sendNow(uni, d)
+		if paused {
+			continue
+		}
+		sent = true
+
+		multicast, destinations := uni.addresses()
+		if multicast {
+			syncUni.multicast = true
+		}
+		for _, dest := range destinations {
+			if !seen[dest.String()] {
+				seen[dest.String()] = true
+				syncUni.destinations = append(syncUni.destinations, dest)
+			}
+		}
+	}
+
+	if !sent { // nothing to synchronise
+		return nil
+	}
+
+	p := packet.NewSyncPacket()
+	p.CID = s.cid
+	p.SyncAddress = syncUniverse
+	p.Sequence = s.nextSyncSequence(syncUniverse)
+	s.sendPacket(syncUni, p)
+
+	return nil
+}
+
+// nextSyncSequence returns the sequence number of the next SyncPacket sent on a sync address.
+// SyncPackets have their own sequence, shared by sync groups and SyncPackets written to the universe of the sync address.
+func (s *Sender) nextSyncSequence(syncAddress uint16) uint8 {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	s.syncSequences[syncAddress] += 1
+	return s.syncSequences[syncAddress]
+}
@@ -0,0 +1,138 @@
+package sacn
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gitlab.com/patopest/go-sacn/packet"
+)
+
+func TestSenderSyncGroup(t *testing.T) {
+	wire := newWireCapture(t)
+	s, err := NewSender("127.0.0.1", &SenderOptions{KeepAlive: 10 * time.Second, DiscoveryInterval: -1})
+	if err != nil {
+		t.Fatalf("Could not create sender: %v", err)
+	}
+	defer s.Close()
+	startCapturedUniverse(t, s, 1)
+	startCapturedUniverse(t, s, 2)
+	if err := s.StartSyncGroup(10, []uint16{1, 2}); err != nil {
+		t.Fatalf("Could not start sync group: %v", err)
+	}
+
+	sendFrame := func(level byte) {
+		frame := make(map[uint16]*packet.DataPacket)
+		for _, universe := range []uint16{1, 2} {
+			p := packet.NewDataPacket()
+			p.SetData([]byte{level})
+			frame[universe] = p
+		}
+		if err := s.SendSyncFrame(10, frame); err != nil {
+			t.Fatalf("Could not send sync frame: %v", err)
+		}
+	}
+	expectUnsynchronised := func(universe uint16, sequence uint8, level byte, syncAddress uint16) {
+		d := wire.nextData(t, time.Second)
+		if d.Universe != universe || d.Sequence != sequence || d.GetData()[0] != level {
+			t.Fatalf("unexpected data packet on universe %d:\n- want: sequence %d, level %d\n-  got: universe %d, sequence %d, level %d", universe, sequence, level, d.Universe, d.Sequence, d.GetData()[0])
+		}
+		if d.SyncAddress != syncAddress {
+			t.Fatalf("unexpected sync address on universe %d:\n- want: %d\n-  got: %d", universe, syncAddress, d.SyncAddress)
+		}
+	}
+	expectData := func(universe uint16, sequence uint8, level byte) {
+		expectUnsynchronised(universe, sequence, level, 10)
+	}
+	expectSync := func(sequence uint8) {
+		p := wire.next(t, time.Second)
+		sync, ok := p.(*packet.SyncPacket)
+		if !ok || sync.Sequence != sequence || sync.SyncAddress != 10 {
+			t.Fatalf("unexpected sync packet:\n- want: SyncPacket{sequence: %d, sync address: %d}\n-  got: %v", sequence, 10, describePacket(p))
+		}
+	}
+
+	// The members' data is sent first with their own sequence, then a single SyncPacket with the group's sequence
+	sendFrame(1)
+	expectData(1, 1, 1)
+	expectData(2, 1, 1)
+	expectSync(1)
+	sendFrame(2)
+	expectData(1, 2, 2)
+	expectData(2, 2, 2)
+	expectSync(2)
+
+	// The frame becomes the state of the members
+	for _, universe := range []uint16{1, 2} {
+		levels, _ := s.GetSlots(universe)
+		if levels[0] != 2 {
+			t.Fatalf("unexpected level of universe %d after sync frame:\n- want: %d\n-  got: %d", universe, 2, levels[0])
+		}
+	}
+
+	// Paused members are skipped but the other members are still synchronised
+	s.PauseUniverse(2)
+	sendFrame(3)
+	expectData(1, 3, 3)
+	expectSync(3)
+
+	// Nothing is sent when all members are paused
+	s.PauseUniverse(1)
+	sendFrame(4)
+	if p := wire.next(t, 100*time.Millisecond); p != nil {
+		t.Fatalf("unexpected packet while all members are paused: %v", describePacket(p))
+	}
+
+	// A resumed member sends the last frame it received, without waiting for a SyncPacket
+	s.ResumeUniverse(2)
+	expectUnsynchronised(2, 3, 4, 0)
+	if p := wire.next(t, 100*time.Millisecond); p != nil {
+		t.Fatalf("unexpected packet after resume: %v", describePacket(p))
+	}
+
+	// SyncPackets written to the sync universe share the sequence of the sync group
+	s.ResumeUniverse(1)
+	expectUnsynchronised(1, 4, 4, 0)
+	startCapturedUniverse(t, s, 10)
+	s.Send(10, packet.NewSyncPacket())
+	expectSync(4)
+	sendFrame(5)
+	expectData(1, 5, 5)
+	expectData(2, 4, 5)
+	expectSync(5)
+}
+
+func TestSendSyncFrameErrors(t *testing.T) {
+	s, err := NewSender("127.0.0.1", &SenderOptions{DiscoveryInterval: -1})
+	if err != nil {
+		t.Fatalf("Could not create sender: %v", err)
+	}
+	defer s.Close()
+	s.StartUniverse(1)
+	s.StartUniverse(2)
+	s.StartSyncGroup(10, []uint16{1, 3})
+
+	tests := []struct {
+		name     string
+		sync     uint16
+		frame    map[uint16]*packet.DataPacket
+		universe uint16
+		err      error
+	}{
+		{name: "Unknown sync group", sync: 11, frame: map[uint16]*packet.DataPacket{1: packet.NewDataPacket()}, universe: 11, err: ErrSyncGroupNotFound},
+		{name: "Not a member", sync: 10, frame: map[uint16]*packet.DataPacket{2: packet.NewDataPacket()}, universe: 2, err: ErrNotSyncGroupMember},
+		{name: "Member not started", sync: 10, frame: map[uint16]*packet.DataPacket{3: packet.NewDataPacket()}, universe: 3, err: ErrUniverseNotFound},
+		{name: "Nil packet", sync: 10, frame: map[uint16]*packet.DataPacket{1: nil}, universe: 1, err: ErrMissingPacket},
+	}
+
+	for _, tt := range tests {
+		err := s.SendSyncFrame(tt.sync, tt.frame)
+		if !errors.Is(err, tt.err) {
+			t.Fatalf("unexpected error on \"%s\":\n- want: %v\n-  got: %v", tt.name, tt.err, err)
+		}
+		var uniErr *UniverseError
+		if !errors.As(err, &uniErr) || uniErr.Universe != tt.universe {
+			t.Fatalf("unexpected universe in error on \"%s\":\n- want: %d\n-  got: %v", tt.name, tt.universe, err)
+		}
+	}
+}